@@ -0,0 +1,20 @@
+// Package auth carries the principal a request authenticated as, set by the
+// authentication of the services once the credentials of the request are
+// verified, over HTTP or gRPC
+package auth
+
+import "context"
+
+type principalKey struct{}
+
+// WithPrincipal returns ctx carrying the user a request authenticated as
+func WithPrincipal(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, principalKey{}, user)
+}
+
+// Principal returns the user the request of ctx authenticated as, false if
+// it did not authenticate
+func Principal(ctx context.Context) (string, bool) {
+	user, ok := ctx.Value(principalKey{}).(string)
+	return user, ok && user != ""
+}
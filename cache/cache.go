@@ -0,0 +1,135 @@
+// Package cache caches the values read from the database, in process
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// metrics of the caches, by name
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_requests_total",
+		Help: "Number of the lookups of the caches, by result: hit or miss.",
+	}, []string{"cache", "result"})
+	evictionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_evictions_total",
+		Help: "Number of the values evicted from the caches to make room.",
+	}, []string{"cache"})
+)
+
+// Cache is a key value cache. Values are shared by all their readers, who
+// must not modify them. Purge invalidates all the values, including the
+// ones being loaded
+type Cache interface {
+	Get(key string) (interface{}, bool)
+	// Generation returns the generation of the cache, to be given to Set
+	// along with the values loaded after
+	Generation() uint64
+	// Set caches a value, unless the cache has been purged since gen
+	Set(key string, value interface{}, gen uint64)
+	Purge()
+}
+
+type entry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+// LRU is a cache of a bounded number of values, each of them for a TTL,
+// evicting the least recently used one to make room. It is safe for
+// concurrent use
+type LRU struct {
+	name  string
+	size  int
+	ttl   time.Duration
+	mu    sync.Mutex
+	gen   uint64
+	order *list.List
+	items map[string]*list.Element
+}
+
+// NewLRU returns an LRU cache of size values kept for ttl, its metrics are
+// those of name
+func NewLRU(name string, size int, ttl time.Duration) *LRU {
+	return &LRU{name: name, size: size, ttl: ttl, order: list.New(), items: map[string]*list.Element{}}
+}
+
+// Get returns the value of key
+func (c *LRU) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if ok && time.Now().After(el.Value.(*entry).expires) {
+		c.remove(el)
+		ok = false
+	}
+	if !ok {
+		requestsTotal.WithLabelValues(c.name, "miss").Inc()
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	requestsTotal.WithLabelValues(c.name, "hit").Inc()
+	return el.Value.(*entry).value, true
+}
+
+// Generation returns the generation of the cache
+func (c *LRU) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen
+}
+
+// Set caches value
+func (c *LRU) Set(key string, value interface{}, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+	c.items[key] = c.order.PushFront(&entry{key: key, value: value, expires: time.Now().Add(c.ttl)})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+		evictionsTotal.WithLabelValues(c.name).Inc()
+	}
+}
+
+// Purge removes all the values
+func (c *LRU) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.order.Init()
+	c.items = map[string]*list.Element{}
+}
+
+func (c *LRU) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*entry).key)
+}
+
+// Load returns the value of key from c, loading and caching it on a miss;
+// a nil c caches nothing
+func Load(c Cache, key string, load func() (interface{}, error)) (interface{}, error) {
+	if c == nil {
+		return load()
+	}
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+	gen := c.Generation()
+	v, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.Set(key, v, gen)
+	return v, nil
+}
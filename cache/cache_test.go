@@ -0,0 +1,29 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadDropsValuesOfPurgedGeneration(t *testing.T) {
+	c := NewLRU("test", 10, time.Minute)
+	// a change purges the cache while the value is being loaded, the value
+	// read before the change is not cached
+	v, err := Load(c, "list:1", func() (interface{}, error) {
+		c.Purge()
+		return "before", nil
+	})
+	if err != nil || v != "before" {
+		t.Fatalf("Load = %v, %v", v, err)
+	}
+	if v, ok := c.Get("list:1"); ok {
+		t.Errorf("Get = %v, want the value loaded before the purge dropped", v)
+	}
+
+	if _, err := Load(c, "list:1", func() (interface{}, error) { return "after", nil }); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := c.Get("list:1"); !ok || v != "after" {
+		t.Errorf("Get = %v, %v, want the value loaded after the purge", v, ok)
+	}
+}
@@ -0,0 +1,198 @@
+// Package changes serves the feed of the changes made to the lists, items
+// and users. The services write the changes to the change log with Write, in
+// the transaction of every change, the changes being numbered last under a
+// lock held until it commits, so that they are numbered in the order of
+// their commits and a consumer polling with the cursor of the last change it
+// read misses none of them
+package changes
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrCursor is returned for a malformed cursor
+var ErrCursor = errors.New("invalid cursor")
+
+// Operations of the changes
+const (
+	OpInsert = "insert"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+// Change of an entity, Before and After being its rows before and after the
+// change, null for an insert and a delete respectively. Version is the
+// number of the change among the changes of the entity, 1 for the first one
+type Change struct {
+	Cursor  Cursor          `json:"cursor"`
+	Entity  string          `json:"entity"`
+	ID      int64           `json:"id"`
+	Op      string          `json:"op"`
+	Before  json.RawMessage `json:"before"`
+	After   json.RawMessage `json:"after"`
+	Version int64           `json:"version"`
+	At      time.Time       `json:"at"`
+}
+
+// Cursor is the position of a change in the feed, the zero cursor being the
+// start of the feed
+type Cursor struct {
+	seq int64
+}
+
+// ParseCursor parses the cursor of a change, "" being the start of the feed
+func ParseCursor(s string) (Cursor, error) {
+	if s == "" {
+		return Cursor{}, nil
+	}
+	seq, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || seq < 0 {
+		return Cursor{}, ErrCursor
+	}
+	return Cursor{seq}, nil
+}
+
+// String returns the cursor as given to ParseCursor
+func (c Cursor) String() string {
+	if c == (Cursor{}) {
+		return ""
+	}
+	return strconv.FormatInt(c.seq, 10)
+}
+
+// MarshalText encodes the cursor as a string
+func (c Cursor) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// Feed of the changes of a database, reading PollInterval apart when
+// followed, every read being bounded by Timeout
+type Feed struct {
+	db           *sql.DB
+	PollInterval time.Duration
+	Timeout      time.Duration
+}
+
+// NewFeed returns the feed of the changes of db
+func NewFeed(db *sql.DB) *Feed {
+	return &Feed{db: db, PollInterval: time.Second, Timeout: 10 * time.Second}
+}
+
+// Since returns the changes following a cursor, up to limit of them and only
+// of entity when given
+func (f *Feed) Since(ctx context.Context, since Cursor, entity string, limit int) ([]*Change, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.Timeout)
+	defer cancel()
+	const query = `SELECT seq, entity, entity_id, op, before, after, version, at FROM changes.events
+		WHERE seq > $1 AND ($2 = '' OR entity = $2) ORDER BY seq LIMIT $3`
+	rows, err := f.db.QueryContext(ctx, query, since.seq, entity, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	changes := []*Change{}
+	for rows.Next() {
+		c := &Change{}
+		var before, after []byte
+		if err := rows.Scan(&c.Cursor.seq, &c.Entity, &c.ID, &c.Op, &before, &after, &c.Version, &c.At); err != nil {
+			return nil, err
+		}
+		c.Before, c.After = before, after
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// Scope of the changes of a transaction: the lists, items and users it
+// changed
+type Scope struct {
+	Lists []int64
+	Items []int64
+	Users []int64
+}
+
+// lockKey is the key of the advisory lock serializing the numbering of the
+// changes
+const lockKey = 0x6368616e676573
+
+// logQuery logs the rows of a scope which aren't as last logged, the version
+// of a change following the one of the latest change of its entity. The row
+// locks taken by the transaction on the rows it changed keep any other from
+// logging them until it commits
+const logQuery = `WITH scope AS (
+	SELECT 'todo_list'::text AS entity, unnest($1::bigint[]) AS id
+	UNION SELECT 'todo_item', unnest($2::bigint[])
+	UNION SELECT 'user', unnest($3::bigint[])
+), live AS (
+	SELECT entity, id, data FROM changes.rows
+	WHERE (entity = 'todo_list' AND id = ANY($1)) OR (entity = 'todo_item' AND id = ANY($2)) OR (entity = 'user' AND id = ANY($3))
+)
+INSERT INTO changes.events (entity, entity_id, version, op, before, after)
+SELECT s.entity, s.id, COALESCE(l.version, 0) + 1,
+	CASE WHEN l.after IS NULL THEN 'insert' WHEN c.data IS NULL THEN 'delete' ELSE 'update' END, l.after, c.data
+FROM scope s
+LEFT JOIN live c ON c.entity = s.entity AND c.id = s.id
+LEFT JOIN LATERAL (
+	SELECT version, after FROM changes.events e
+	WHERE e.entity = s.entity AND e.entity_id = s.id ORDER BY version DESC LIMIT 1
+) l ON true
+WHERE c.data IS DISTINCT FROM l.after
+ORDER BY s.entity, s.id
+RETURNING id`
+
+// numberQuery numbers the changes logged by a transaction
+const numberQuery = `UPDATE changes.events e SET seq = n.seq
+FROM (SELECT id, nextval('changes.events_seq') AS seq FROM unnest($1::bigint[]) AS id ORDER BY id) n
+WHERE e.id = n.id`
+
+// Write writes to the log the changes tx made to the lists, items and users
+// of scope. It must be the last statement of tx before its commit: it only
+// logs the changes made so far, and numbers them under a lock held until
+// then, so that the changes are numbered in the order of the commits
+func Write(ctx context.Context, tx *sql.Tx, scope Scope) error {
+	if len(scope.Lists) == 0 && len(scope.Items) == 0 && len(scope.Users) == 0 {
+		return nil
+	}
+	rows, err := tx.QueryContext(ctx, logQuery, array(scope.Lists), array(scope.Items), array(scope.Users))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, lockKey); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, numberQuery, pq.Array(ids))
+	return err
+}
+
+// array returns the PostgreSQL array of ids, empty when nil
+func array(ids []int64) interface{} {
+	if ids == nil {
+		ids = []int64{}
+	}
+	return pq.Array(ids)
+}
@@ -0,0 +1,115 @@
+package changes
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/dbtest"
+)
+
+func TestParseCursor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Cursor
+		wantErr bool
+	}{
+		{"", Cursor{}, false},
+		{"42", Cursor{42}, false},
+		{"-1", Cursor{}, true},
+		{"12.42", Cursor{}, true},
+		{"next", Cursor{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCursor(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseCursor(%q) = %v, %v", tt.in, got, err)
+		}
+		if err == nil && got.String() != tt.in {
+			t.Errorf("ParseCursor(%q).String() = %q", tt.in, got.String())
+		}
+	}
+}
+
+func TestSince(t *testing.T) {
+	at := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	f := NewFeed(dbtest.Open(func(query string, args []driver.Value) ([][]driver.Value, error) {
+		if !strings.Contains(query, "ORDER BY seq") {
+			t.Errorf("query = %q, want the changes in the order of seq", query)
+		}
+		if args[0] != int64(41) || args[1] != "todo_item" || args[2] != int64(2) {
+			t.Errorf("args = %v", args)
+		}
+		return [][]driver.Value{
+			{int64(42), "todo_item", int64(7), "insert", nil, []byte(`{"id":7}`), int64(1), at},
+			{int64(45), "todo_item", int64(7), "delete", []byte(`{"id":7}`), nil, int64(2), at},
+		}, nil
+	}))
+	got, err := f.Since(context.Background(), Cursor{41}, "todo_item", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Cursor != (Cursor{42}) || got[1].Version != 2 || got[1].Op != OpDelete || got[1].After != nil {
+		t.Errorf("Since() = %+v, %+v", got[0], got[1])
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		scope  Scope
+		logged [][]driver.Value
+		want   []string
+	}{
+		{"empty", Scope{}, nil, nil},
+		{"unchanged", Scope{Lists: []int64{1}}, nil, []string{"INSERT INTO changes.events"}},
+		{"items", Scope{Lists: []int64{1}, Items: []int64{2, 3}}, [][]driver.Value{{int64(8)}, {int64(9)}},
+			[]string{"INSERT INTO changes.events", "pg_advisory_xact_lock", "UPDATE changes.events"}},
+		{"users", Scope{Users: []int64{4}}, [][]driver.Value{{int64(10)}},
+			[]string{"INSERT INTO changes.events", "pg_advisory_xact_lock", "UPDATE changes.events"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			var args [][]driver.Value
+			db := dbtest.Open(func(query string, a []driver.Value) ([][]driver.Value, error) {
+				args = append(args, a)
+				for _, want := range []string{"INSERT INTO changes.events", "pg_advisory_xact_lock", "UPDATE changes.events"} {
+					if strings.Contains(query, want) {
+						got = append(got, want)
+						if want == "INSERT INTO changes.events" {
+							return tt.logged, nil
+						}
+					}
+				}
+				return nil, nil
+			})
+			tx, err := db.Begin()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Write(context.Background(), tx, tt.scope); err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("statements = %v, want %v", got, tt.want)
+			}
+			// the arrays are given as their PostgreSQL literals
+			switch tt.name {
+			case "items":
+				if args[0][0] != "{1}" || args[0][1] != "{2,3}" || args[0][2] != "{}" {
+					t.Errorf("scope args = %v", args[0])
+				}
+				if args[1][0] != int64(lockKey) || args[2][0] != "{8,9}" {
+					t.Errorf("numbering args = %v, %v", args[1], args[2])
+				}
+			case "users":
+				if args[0][0] != "{}" || args[0][1] != "{}" || args[0][2] != "{4}" {
+					t.Errorf("scope args = %v", args[0])
+				}
+			}
+		})
+	}
+}
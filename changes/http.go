@@ -0,0 +1,132 @@
+package changes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/httperr"
+	"github.com/Shivam010/go-rest-api/logging"
+	"github.com/Shivam010/go-rest-api/server"
+	"github.com/Shivam010/go-rest-api/tracing"
+)
+
+// page sizes of the feed
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// Page of the feed, Next being the cursor to read the following one from
+type Page struct {
+	Changes []*Change `json:"changes"`
+	Next    Cursor    `json:"next"`
+}
+
+// Changes serves the changes following the ?since= cursor, ?limit= at a
+// time and only of the ?entity= when given. It answers with a Page, or with
+// a stream of the changes as newline delimited JSON when asked for with
+// ?format=ndjson or an Accept: application/x-ndjson header, which ends once
+// the feed is read unless ?follow=true
+func (f *Feed) Changes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	q := r.URL.Query()
+	since, err := ParseCursor(q.Get("since"))
+	if err != nil {
+		httperr.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit := defaultLimit
+	if v := q.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > maxLimit {
+			httperr.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+	entity := q.Get("entity")
+
+	if q.Get("format") == "ndjson" || strings.Contains(r.Header.Get("Accept"), "application/x-ndjson") {
+		f.stream(w, r, since, entity, limit, q.Get("follow") == "true")
+		return
+	}
+	changes, err := f.Since(r.Context(), since, entity, limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	page := &Page{Changes: changes, Next: since}
+	if len(changes) != 0 {
+		page.Next = changes[len(changes)-1].Cursor
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// stream writes the changes following since a line each, waiting for the
+// next ones when following the feed
+func (f *Feed) stream(w http.ResponseWriter, r *http.Request, since Cursor, entity string, limit int, follow bool) {
+	rc := http.NewResponseController(w)
+	ctx := r.Context()
+	if follow {
+		// the stream outlives the write timeout of the server, and ends when
+		// the server shuts down
+		rc.SetWriteDeadline(time.Time{})
+		var cancel context.CancelFunc
+		ctx, cancel = server.StreamContext(ctx)
+		defer cancel()
+	}
+	enc := json.NewEncoder(w)
+	started := false
+	for {
+		changes, err := f.Since(ctx, since, entity, limit)
+		if err != nil {
+			if !started {
+				writeError(w, r, err)
+			} else if ctx.Err() == nil {
+				logging.FromContext(ctx).Error("change feed stream failed", "err", err)
+			}
+			return
+		}
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("X-Accel-Buffering", "no")
+			started = true
+		}
+		for _, c := range changes {
+			if err := enc.Encode(c); err != nil {
+				return
+			}
+			since = c.Cursor
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+		if len(changes) == limit {
+			continue
+		}
+		if !follow {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(f.PollInterval):
+		}
+	}
+}
+
+// writeError answers with a server error
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	ref := ""
+	if id := tracing.TraceID(r.Context()); id != "" {
+		ref = " (trace id " + id + ")"
+	}
+	httperr.Error(w, "500 Internal Server Error"+ref, http.StatusInternalServerError)
+	logging.FromContext(r.Context()).Error("request failed", "err", err)
+}
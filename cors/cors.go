@@ -0,0 +1,169 @@
+// Package cors lets browser clients of the allowed origins call the
+// services, answering their preflight requests
+package cors
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Shivam010/go-rest-api/httperr"
+)
+
+// Options of the cross-origin requests
+type Options struct {
+	// AllowedOrigins are the origins allowed, "*" for any and
+	// "https://*.example.com" for the subdomains of one; none disables CORS.
+	// The origins allowed by "*" only are answered with "*", without
+	// credentials
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long, in seconds, a preflight may be cached
+	MaxAge int
+}
+
+// FromEnv returns the options of the environment:
+//   - CORS_ALLOWED_ORIGINS, a comma separated list, none by default
+//   - CORS_ALLOWED_METHODS, GET, POST, PUT, PATCH and DELETE by default
+//   - CORS_ALLOWED_HEADERS, those the services read by default
+//   - CORS_ALLOW_CREDENTIALS, true to let browsers send credentials
+//   - CORS_MAX_AGE, 600 seconds by default
+//
+// Any origin can't be allowed along with the credentials, a browser sending
+// them to a service answering every origin.
+func FromEnv() (*Options, error) {
+	o := &Options{
+		AllowedOrigins: list(os.Getenv("CORS_ALLOWED_ORIGINS"), nil),
+		AllowedMethods: list(os.Getenv("CORS_ALLOWED_METHODS"), []string{"GET", "POST", "PUT", "PATCH", "DELETE"}),
+		AllowedHeaders: list(os.Getenv("CORS_ALLOWED_HEADERS"), []string{"Authorization", "Content-Type", "X-Request-ID", "traceparent", "tracestate", "Idempotency-Key"}),
+		ExposedHeaders: []string{
+			"X-Request-ID", "X-Trace-ID", "Retry-After", "Idempotent-Replayed",
+			"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy",
+		},
+		MaxAge: 600,
+	}
+	o.AllowCredentials, _ = strconv.ParseBool(os.Getenv("CORS_ALLOW_CREDENTIALS"))
+	if age, err := strconv.Atoi(os.Getenv("CORS_MAX_AGE")); err == nil && age >= 0 {
+		o.MaxAge = age
+	}
+	if o.AllowCredentials && contains(o.AllowedOrigins, "*") {
+		return nil, errors.New("cors: any origin can't be allowed with credentials")
+	}
+	return o, nil
+}
+
+func list(s string, fallback []string) []string {
+	out := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	if len(out) == 0 {
+		return fallback
+	}
+	return out
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin of an origin: the
+// origin itself when listed, "*" when allowed as any origin, empty if not
+// allowed
+func (o *Options) allowOrigin(origin string) string {
+	anyOrigin := false
+	for _, allowed := range o.AllowedOrigins {
+		if allowed == "*" {
+			anyOrigin = true
+			continue
+		}
+		if strings.EqualFold(allowed, origin) {
+			return origin
+		}
+		if i := strings.Index(allowed, "://*."); i >= 0 {
+			scheme, domain := allowed[:i+3], allowed[i+4:]
+			if strings.HasPrefix(origin, scheme) && strings.HasSuffix(origin, domain) && len(origin) > len(scheme)+len(domain) {
+				return origin
+			}
+		}
+	}
+	if anyOrigin {
+		return "*"
+	}
+	return ""
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler answers the preflight requests itself, ahead of the methods
+// checks and of the authentication of next, and adds the CORS headers to
+// the responses of next to the allowed origins
+func (o *Options) Handler(next http.Handler) http.Handler {
+	if len(o.AllowedOrigins) == 0 {
+		return next
+	}
+	methods := strings.Join(o.AllowedMethods, ", ")
+	exposed := strings.Join(o.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(o.MaxAge)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		if preflight {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		allowOrigin := ""
+		if origin != "" {
+			allowOrigin = o.allowOrigin(origin)
+		}
+		if allowOrigin == "" {
+			if preflight {
+				httperr.Error(w, "403 Origin Not Allowed", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h.Set("Access-Control-Allow-Origin", allowOrigin)
+		if o.AllowCredentials && allowOrigin != "*" {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+		if !preflight {
+			if exposed != "" {
+				h.Set("Access-Control-Expose-Headers", exposed)
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !contains(o.AllowedMethods, r.Header.Get("Access-Control-Request-Method")) {
+			httperr.Error(w, "403 Method Not Allowed", http.StatusForbidden)
+			return
+		}
+		for _, header := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+			if header = strings.TrimSpace(header); header != "" && !contains(o.AllowedHeaders, header) {
+				httperr.Error(w, "403 Header Not Allowed", http.StatusForbidden)
+				return
+			}
+		}
+		h.Set("Access-Control-Allow-Methods", methods)
+		h.Set("Access-Control-Allow-Headers", strings.Join(o.AllowedHeaders, ", "))
+		h.Set("Access-Control-Max-Age", maxAge)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
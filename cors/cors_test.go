@@ -0,0 +1,53 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromEnvRefusesAnyOriginWithCredentials(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com, *")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	if _, err := FromEnv(); err == nil {
+		t.Error("FromEnv() allowed any origin with credentials")
+	}
+
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "false")
+	if _, err := FromEnv(); err != nil {
+		t.Errorf("FromEnv() = %v, want any origin allowed without credentials", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     Options
+		origin      string
+		allowOrigin string
+		credentials string
+	}{
+		{"listed origin", Options{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true}, "https://app.example.com", "https://app.example.com", "true"},
+		{"subdomain", Options{AllowedOrigins: []string{"https://*.example.com"}, AllowCredentials: true}, "https://a.example.com", "https://a.example.com", "true"},
+		{"not allowed", Options{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true}, "https://evil.test", "", ""},
+		{"any origin", Options{AllowedOrigins: []string{"*"}}, "https://evil.test", "*", ""},
+		// options not from the environment are never reflected on a wildcard
+		{"any origin with credentials", Options{AllowedOrigins: []string{"*"}, AllowCredentials: true}, "https://evil.test", "*", ""},
+		{"listed before any origin", Options{AllowedOrigins: []string{"*", "https://app.example.com"}, AllowCredentials: true}, "https://app.example.com", "https://app.example.com", "true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := tt.options.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			r := httptest.NewRequest("GET", "/lists", nil)
+			r.Header.Set("Origin", tt.origin)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.allowOrigin)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != tt.credentials {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want %q", got, tt.credentials)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+// Package dbtest is a database/sql driver for the tests, answering the
+// queries of a database with the rows returned by a function of the test
+// rather than with a running PostgreSQL
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Answer returns the rows of a query given its args, every row holding the
+// values of the columns read by the query in order; the rows of a statement
+// executed without reading them are ignored
+type Answer func(query string, args []driver.Value) ([][]driver.Value, error)
+
+var (
+	answers sync.Map
+	next    atomic.Int64
+)
+
+func init() {
+	sql.Register("dbtest", testDriver{})
+}
+
+// Register returns the name of a database of the dbtest driver answering
+// its queries with answer
+func Register(answer Answer) string {
+	name := strconv.FormatInt(next.Add(1), 10)
+	answers.Store(name, answer)
+	return name
+}
+
+// Open returns a database answering its queries with answer
+func Open(answer Answer) *sql.DB {
+	db, _ := sql.Open("dbtest", Register(answer))
+	return db
+}
+
+type testDriver struct{}
+
+func (testDriver) Open(name string) (driver.Conn, error) {
+	answer, ok := answers.Load(name)
+	if !ok {
+		return nil, errors.New("dbtest: unknown database " + name)
+	}
+	return conn{answer.(Answer)}, nil
+}
+
+type conn struct {
+	answer Answer
+}
+
+func (c conn) Prepare(query string) (driver.Stmt, error) {
+	return stmt{c, query}, nil
+}
+
+func (c conn) Close() error {
+	return nil
+}
+
+func (c conn) Begin() (driver.Tx, error) {
+	return tx{}, nil
+}
+
+func (c conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	values, err := c.answer(query, valuesOf(args))
+	if err != nil {
+		return nil, err
+	}
+	return &rows{values: values}, nil
+}
+
+func (c conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	values, err := c.answer(query, valuesOf(args))
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(len(values)), nil
+}
+
+func valuesOf(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
+
+// stmt is a prepared statement, answered as its query
+type stmt struct {
+	conn  conn
+	query string
+}
+
+func (s stmt) Close() error {
+	return nil
+}
+
+func (s stmt) NumInput() int {
+	return -1
+}
+
+func (s stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedOf(args))
+}
+
+func (s stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedOf(args))
+}
+
+func (s stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func namedOf(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+type tx struct{}
+
+func (tx) Commit() error   { return nil }
+func (tx) Rollback() error { return nil }
+
+type rows struct {
+	values [][]driver.Value
+}
+
+func (r *rows) Columns() []string {
+	if len(r.values) == 0 {
+		return nil
+	}
+	columns := make([]string, len(r.values[0]))
+	for i := range columns {
+		columns[i] = "column" + strconv.Itoa(i+1)
+	}
+	return columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -0,0 +1,176 @@
+// Package health answers the liveness and readiness probes of the services
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/migrations"
+)
+
+// Status of a check, a service is ready unless one of its checks fails
+type Status string
+
+// Check statuses
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Result of a check
+type Result struct {
+	Status  Status      `json:"status"`
+	Error   string      `json:"error,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// Report of the readiness of a service
+type Report struct {
+	Status Status             `json:"status"`
+	Checks map[string]*Result `json:"checks"`
+}
+
+// Check reports on one of the dependencies of a service
+type Check func(ctx context.Context) *Result
+
+// Checker runs the readiness checks of a service concurrently, each of them
+// within Timeout
+type Checker struct {
+	checks  map[string]Check
+	Timeout time.Duration
+}
+
+// NewChecker returns a checker of the database of a service, of its pending
+// migrations and of the saturation of its connection pool
+func NewChecker(db *sql.DB) *Checker {
+	c := &Checker{checks: map[string]Check{}, Timeout: 2 * time.Second}
+	c.Add("database", Database(db))
+	c.Add("migrations", Migrations(db))
+	c.Add("pool", Pool(db))
+	return c
+}
+
+// Add adds a check under name
+func (c *Checker) Add(name string, check Check) {
+	c.checks[name] = check
+}
+
+// Run runs all the checks
+func (c *Checker) Run(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	report := &Report{Status: StatusOK, Checks: make(map[string]*Result, len(c.checks))}
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for name, check := range c.checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			res := check(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = res
+			if res.Status == StatusFail || (res.Status == StatusWarn && report.Status == StatusOK) {
+				report.Status = res.Status
+			}
+		}(name, check)
+	}
+	wg.Wait()
+	return report
+}
+
+// Live answers the liveness probe, the process is alive if it answers
+func Live(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, &Report{Status: StatusOK, Checks: map[string]*Result{}})
+}
+
+// Ready answers the readiness probe with the report of the checks, with a
+// 503 if one of them fails; the report tells about the internals of the
+// service, it is only served on the admin server
+func (c *Checker) Ready(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+	writeJSON(w, readyCode(report), report)
+}
+
+// Status answers the readiness probe like Ready, with the status of the
+// report only, for the probes made on the api server
+func (c *Checker) Status(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+	writeJSON(w, readyCode(report), &Report{Status: report.Status, Checks: map[string]*Result{}})
+}
+
+// readyCode returns the status code of a readiness report
+func readyCode(report *Report) int {
+	if report.Status == StatusFail {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// fail returns the failed result of err, telling a timeout apart
+func fail(err error, details interface{}) *Result {
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = errors.New("timed out")
+	}
+	return &Result{Status: StatusFail, Error: err.Error(), Details: details}
+}
+
+// Database checks that the database can be reached
+func Database(db *sql.DB) Check {
+	return func(ctx context.Context) *Result {
+		start := time.Now()
+		if err := db.PingContext(ctx); err != nil {
+			return fail(err, nil)
+		}
+		return &Result{Status: StatusOK, Details: map[string]string{"latency": time.Since(start).String()}}
+	}
+}
+
+// Migrations checks that all the migrations have been applied
+func Migrations(db *sql.DB) Check {
+	return func(ctx context.Context) *Result {
+		pending, err := migrations.Pending(ctx, db)
+		if err != nil {
+			return fail(err, nil)
+		}
+		details := map[string][]string{"pending": pending}
+		if len(pending) != 0 {
+			return &Result{Status: StatusFail, Error: "pending migrations", Details: details}
+		}
+		return &Result{Status: StatusOK, Details: details}
+	}
+}
+
+// Pool warns when all the connections of the pool are in use, requests then
+// waiting for one
+func Pool(db *sql.DB) Check {
+	return func(ctx context.Context) *Result {
+		stats := db.Stats()
+		details := map[string]interface{}{
+			"open":          stats.OpenConnections,
+			"in_use":        stats.InUse,
+			"idle":          stats.Idle,
+			"max_open":      stats.MaxOpenConnections,
+			"wait_count":    stats.WaitCount,
+			"wait_duration": stats.WaitDuration.String(),
+		}
+		if stats.MaxOpenConnections > 0 && stats.InUse >= stats.MaxOpenConnections {
+			return &Result{Status: StatusWarn, Error: "connection pool saturated", Details: details}
+		}
+		return &Result{Status: StatusOK, Details: details}
+	}
+}
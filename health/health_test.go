@@ -0,0 +1,59 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReady(t *testing.T) {
+	pool := func(ctx context.Context) *Result {
+		return &Result{Status: StatusOK, Details: map[string]int{"in_use": 3}}
+	}
+	database := func(ctx context.Context) *Result {
+		return fail(errors.New("connection refused"), nil)
+	}
+
+	tests := []struct {
+		name    string
+		checks  map[string]Check
+		handler func(*Checker) http.HandlerFunc
+		code    int
+		want    []string
+		hidden  []string
+	}{
+		{"ready report", map[string]Check{"pool": pool}, func(c *Checker) http.HandlerFunc { return c.Ready },
+			http.StatusOK, []string{`"status":"ok"`, `"in_use":3`}, nil},
+		{"failed report", map[string]Check{"pool": pool, "database": database}, func(c *Checker) http.HandlerFunc { return c.Ready },
+			http.StatusServiceUnavailable, []string{`"status":"fail"`, "connection refused"}, nil},
+		{"ready status", map[string]Check{"pool": pool}, func(c *Checker) http.HandlerFunc { return c.Status },
+			http.StatusOK, []string{`"status":"ok"`}, []string{"in_use"}},
+		{"failed status", map[string]Check{"pool": pool, "database": database}, func(c *Checker) http.HandlerFunc { return c.Status },
+			http.StatusServiceUnavailable, []string{`"status":"fail"`}, []string{"in_use", "connection refused"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Checker{checks: tt.checks, Timeout: time.Second}
+			w := httptest.NewRecorder()
+			tt.handler(c)(w, httptest.NewRequest("GET", "/readyz", nil))
+			if w.Code != tt.code {
+				t.Errorf("code = %d, want %d", w.Code, tt.code)
+			}
+			body := w.Body.String()
+			for _, s := range tt.want {
+				if !strings.Contains(body, s) {
+					t.Errorf("body = %s, want %s in it", body, s)
+				}
+			}
+			for _, s := range tt.hidden {
+				if strings.Contains(body, s) {
+					t.Errorf("body = %s, want no %s in it", body, s)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+// Package httperr answers the failed requests of the services with the JSON
+// error body their OpenAPI documents describe
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Body of an error response, Error being the message of the error, the
+// server errors carrying the trace id of the request
+type Body struct {
+	Status int    `json:"status"`
+	Error  string `json:"error"`
+}
+
+// Error answers with the status code and the message of an error, like
+// http.Error does with a text/plain one
+func Error(w http.ResponseWriter, msg string, code int) {
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Type", "application/json")
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(&Body{Status: code, Error: msg})
+}
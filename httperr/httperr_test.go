@@ -0,0 +1,30 @@
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Length", "12")
+	Error(w, `unknown event type: "list.moved"`, http.StatusBadRequest)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if w.Header().Get("Content-Length") != "" {
+		t.Error("the Content-Length of the response is kept")
+	}
+	body := &Body{}
+	if err := json.Unmarshal(w.Body.Bytes(), body); err != nil {
+		t.Fatal(err)
+	}
+	if *body != (Body{Status: 400, Error: `unknown event type: "list.moved"`}) {
+		t.Errorf("body = %+v", body)
+	}
+}
@@ -0,0 +1,246 @@
+// Package idempotency replays the response of a request to the retries of
+// it sent with the same Idempotency-Key
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/auth"
+	"github.com/Shivam010/go-rest-api/httperr"
+)
+
+// Header of the key of a request, and the one marking a replayed response
+const (
+	KeyHeader      = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+// maxBodySize is the size of the largest request body fingerprinted
+const maxBodySize = 1 << 20
+
+// Response of a request, kept for its retries
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Record of a key, without a Response while its first request is served
+type Record struct {
+	Fingerprint string
+	Response    *Response
+	Expires     time.Time
+}
+
+// Store keeps the records of the keys until they expire, the in-memory one
+// is enough for a single instance of a service
+type Store interface {
+	// Reserve keeps a new record of fingerprint for key, or returns the
+	// record of key if it already has one
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Record, bool, error)
+	// Complete keeps the response of the request of a reserved key
+	Complete(ctx context.Context, key string, res *Response) error
+	// Release forgets a key, its request can then be sent again
+	Release(ctx context.Context, key string) error
+}
+
+// MemoryStore keeps the records in memory
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]*Record
+	lastSweep time.Time
+}
+
+// NewMemoryStore returns an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: map[string]*Record{}}
+}
+
+// Reserve reserves key
+func (s *MemoryStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if now.Sub(s.lastSweep) > time.Minute {
+		s.lastSweep = now
+		for k, rec := range s.records {
+			if now.After(rec.Expires) {
+				delete(s.records, k)
+			}
+		}
+	}
+	if rec, ok := s.records[key]; ok && now.Before(rec.Expires) {
+		cp := *rec
+		return &cp, false, nil
+	}
+	rec := &Record{Fingerprint: fingerprint, Expires: now.Add(ttl)}
+	s.records[key] = rec
+	cp := *rec
+	return &cp, true, nil
+}
+
+// Complete keeps the response of key
+func (s *MemoryStore) Complete(ctx context.Context, key string, res *Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rec, ok := s.records[key]; ok {
+		rec.Response = res
+	}
+	return nil
+}
+
+// Release forgets key
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+// Keeper replays the responses of the POST requests with a key, the keys
+// being scoped by the principal a request authenticated as, or else by its
+// payload
+type Keeper struct {
+	store Store
+	ttl   time.Duration
+}
+
+// NewKeeper returns a keeper of the keys for ttl
+func NewKeeper(store Store, ttl time.Duration) *Keeper {
+	return &Keeper{store: store, ttl: ttl}
+}
+
+// recorder keeps a copy of the status, headers and body written by a handler
+type recorder struct {
+	http.ResponseWriter
+	before http.Header
+	res    Response
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if r.res.Status == 0 {
+		r.res.Status = code
+		// the headers set by the handler, not the ones of the outer
+		// middlewares which are set again on a replay
+		r.res.Header = http.Header{}
+		for k, v := range r.Header() {
+			if _, ok := r.before[k]; !ok {
+				r.res.Header[k] = append([]string(nil), v...)
+			}
+		}
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.res.Status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.res.Body = append(r.res.Body, b...)
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// errTooLarge is returned for a body too large to be fingerprinted
+var errTooLarge = errors.New("request body too large")
+
+// fingerprint returns the hash of the method, path and body of a request,
+// putting its body back for the handler
+func fingerprint(r *http.Request) (string, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(body) > maxBodySize {
+		return "", errTooLarge
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Handler replays to the retries of a POST request with an Idempotency-Key
+// the response to its first request, as long as their payload is the same.
+// The response of a request failing with a server error is not kept. The
+// handler is to run after the authentication, which scopes the keys by the
+// principal of a request; the keys of the requests without one are scoped
+// by their payload instead, a retry only being replayed the response to the
+// very same request, and a key reused with another payload being a new key.
+func (k *Keeper) Handler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(KeyHeader)
+		if r.Method != http.MethodPost || key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > 255 {
+			httperr.Error(w, "400 Idempotency-Key Too Long", http.StatusBadRequest)
+			return
+		}
+		fp, err := fingerprint(r)
+		if err != nil {
+			httperr.Error(w, "413 Request Entity Too Large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		ctx := r.Context()
+		scope := "anon:" + fp
+		if principal, ok := auth.Principal(ctx); ok {
+			scope = "user:" + principal
+		}
+		storeKey := scope + " " + key
+		rec, reserved, err := k.store.Reserve(ctx, storeKey, fp, k.ttl)
+		if err != nil {
+			// a store which is down lets the requests through
+			slog.Error("idempotency store error", "err", err)
+			next(w, r)
+			return
+		}
+		if !reserved {
+			switch {
+			case rec.Fingerprint != fp:
+				httperr.Error(w, "422 Idempotency-Key Reused With A Different Payload", http.StatusUnprocessableEntity)
+			case rec.Response == nil:
+				w.Header().Set("Retry-After", "1")
+				httperr.Error(w, "409 Request With This Idempotency-Key In Progress", http.StatusConflict)
+			default:
+				for h, v := range rec.Response.Header {
+					w.Header()[h] = v
+				}
+				w.Header().Set(ReplayedHeader, "true")
+				w.WriteHeader(rec.Response.Status)
+				w.Write(rec.Response.Body)
+			}
+			return
+		}
+
+		rw := &recorder{ResponseWriter: w, before: w.Header().Clone()}
+		next(rw, r)
+		// the key is kept or released even if the request was cancelled, a
+		// request left unanswered can be sent again
+		ctx = context.WithoutCancel(ctx)
+		if rw.res.Status == 0 || rw.res.Status >= 500 {
+			err = k.store.Release(ctx, storeKey)
+		} else {
+			err = k.store.Complete(ctx, storeKey, &rw.res)
+		}
+		if err != nil {
+			slog.Error("idempotency store error", "err", err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/auth"
+)
+
+func TestHandler(t *testing.T) {
+	served := 0
+	h := NewKeeper(NewMemoryStore(), time.Hour).Handler(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(strconv.Itoa(served)))
+	})
+	send := func(principal, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/todolist/addList", strings.NewReader(body))
+		r.Header.Set(KeyHeader, "key")
+		// an unverified username is not a principal
+		r.SetBasicAuth("mavis", "not verified")
+		if principal != "" {
+			r = r.WithContext(auth.WithPrincipal(r.Context(), principal))
+		}
+		w := httptest.NewRecorder()
+		h(w, r)
+		return w
+	}
+
+	tests := []struct {
+		name      string
+		principal string
+		body      string
+		status    int
+		response  string
+		replayed  string
+	}{
+		{"first request", "mavis", "{}", http.StatusCreated, "1", ""},
+		{"retry", "mavis", "{}", http.StatusCreated, "1", "true"},
+		{"retry with a different payload", "mavis", `{"name":"x"}`, http.StatusUnprocessableEntity, "", ""},
+		{"key of another principal", "shivam", "{}", http.StatusCreated, "2", ""},
+		{"unauthenticated request", "", "{}", http.StatusCreated, "3", ""},
+		{"unauthenticated retry", "", "{}", http.StatusCreated, "3", "true"},
+		{"unauthenticated request with another payload", "", `{"name":"x"}`, http.StatusCreated, "4", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := send(tt.principal, tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.response != "" && w.Body.String() != tt.response {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.response)
+			}
+			if got := w.Header().Get(ReplayedHeader); got != tt.replayed {
+				t.Errorf("%s = %q, want %q", ReplayedHeader, got, tt.replayed)
+			}
+		})
+	}
+	if served != 4 {
+		t.Errorf("served %d requests, want 4", served)
+	}
+}
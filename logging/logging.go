@@ -0,0 +1,169 @@
+// Package logging sets up the structured logs of the services, with the
+// request ID and access log of every request
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/tracing"
+)
+
+// Redacted replaces the value of the sensitive attributes
+const Redacted = "[REDACTED]"
+
+// sensitive are the keys of the attributes whose value is never logged
+var sensitive = map[string]bool{
+	"email":    true,
+	"phone":    true,
+	"phoneno":  true,
+	"phone_no": true,
+	"dob":      true,
+	"password": true,
+}
+
+// RequestIDHeader is the header of the ID of a request, honoured when sent
+// by the client and returned in the response
+const RequestIDHeader = "X-Request-ID"
+
+// New returns a JSON logger of the given level, redacting the sensitive
+// attributes
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if sensitive[strings.ToLower(a.Key)] {
+				return slog.String(a.Key, Redacted)
+			}
+			return a
+		},
+	}))
+}
+
+// ParseLevel returns the level of a name, e.g. "debug", info by default
+func ParseLevel(name string) slog.Level {
+	level := slog.LevelInfo
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// context keys of the logger and the ID of a request
+type (
+	ctxKey       struct{}
+	requestIDKey struct{}
+)
+
+// FromContext returns the logger of a request, with its request ID, or the
+// default logger outside of one
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// RequestID returns the ID of a request, empty outside of one
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// newRequestID returns a random 128 bit ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID tells if a client sent ID can be used, it ends up in the
+// logs and the response headers
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// responseRecorder records the status code and size of a response
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Handler gives every request of next an ID, taken from its X-Request-ID
+// header when valid, and a logger with it, and writes its access log
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		logger := slog.Default().With("request_id", id)
+		if traceID := tracing.TraceID(r.Context()); traceID != "" {
+			logger = logger.With("trace_id", traceID)
+		}
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		ctx = context.WithValue(ctx, ctxKey{}, logger)
+		req := r.WithContext(ctx)
+
+		start := time.Now()
+		rec := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, req)
+		// the mux sets the pattern on the request it serves
+		r.Pattern = req.Pattern
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+
+		level := slog.LevelInfo
+		if rec.status >= 500 {
+			level = slog.LevelError
+		}
+		logger.LogAttrs(ctx, level, "request",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.String("route", r.Pattern),
+			slog.Int("status", rec.status),
+			slog.Int("bytes", rec.bytes),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("remote", r.RemoteAddr),
+		)
+	})
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInstrument(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /lists/{id}", Instrument(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "list not found", http.StatusNotFound)
+	}))
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/lists/1", nil))
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/lists/2", nil))
+
+	w := httptest.NewRecorder()
+	Handler(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`http_requests_total{method="GET",route="GET /lists/{id}",status="404"} 2`,
+		`http_request_duration_seconds_count{method="GET",route="GET /lists/{id}",status="404"} 2`,
+		`http_requests_in_flight{route="GET /lists/{id}"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics have no %s", want)
+		}
+	}
+}
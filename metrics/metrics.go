@@ -0,0 +1,26 @@
+// Package metrics records the metrics of the http requests and of the
+// database connection pool of the services, in the default Prometheus
+// registry, and exposes the metrics of the registry
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// handler exposes the metrics of the default registry
+var handler = promhttp.Handler()
+
+// RegisterDBStats registers the collector of the connection pool stats of db
+func RegisterDBStats(db *sql.DB, name string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler writes all the metrics in the Prometheus exposition format
+func Handler(w http.ResponseWriter, r *http.Request) {
+	handler.ServeHTTP(w, r)
+}
@@ -0,0 +1,8 @@
+-- Stable ordering of the items inside a todo list
+ALTER TABLE todolist_management.todo_items ADD COLUMN position bigint NOT NULL DEFAULT 0;
+
+UPDATE todolist_management.todo_items SET position = ranked.rank * 1024
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY list_id ORDER BY id) AS rank FROM todolist_management.todo_items) AS ranked
+WHERE todolist_management.todo_items.id = ranked.id;
+
+CREATE INDEX todo_items_list_position_idx ON todolist_management.todo_items (list_id, position);
@@ -0,0 +1,13 @@
+-- Due dates, priorities, reminders and timestamps of todo items
+ALTER TABLE todolist_management.todo_items
+    ADD COLUMN due_at timestamp with time zone,
+    ADD COLUMN priority text NOT NULL DEFAULT 'normal',
+    ADD COLUMN completed_at timestamp with time zone,
+    ADD COLUMN reminded_at timestamp with time zone,
+    ADD COLUMN created_at timestamp with time zone NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    ADD CONSTRAINT todo_items_priority_check CHECK (priority IN ('low', 'normal', 'high', 'urgent'));
+
+UPDATE todolist_management.todo_items SET completed_at = now() WHERE completed;
+
+CREATE INDEX todo_items_due_at_idx ON todolist_management.todo_items (due_at) WHERE completed IS NOT TRUE;
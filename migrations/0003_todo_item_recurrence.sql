@@ -0,0 +1,7 @@
+-- Recurring todo items, every occurrence of a series points to its first item
+ALTER TABLE todolist_management.todo_items
+    ADD COLUMN recurrence text NOT NULL DEFAULT '',
+    ADD COLUMN series_id integer,
+    ADD COLUMN occurrence integer NOT NULL DEFAULT 1;
+
+CREATE INDEX todo_items_series_id_idx ON todolist_management.todo_items (series_id);
@@ -0,0 +1,10 @@
+-- An occurrence of a recurring item is generated once, completing an item
+-- again after reopening it doesn't add its next occurrence twice; of the
+-- occurrences generated more than once before, the completed one, or else
+-- the first one, is kept
+DELETE FROM todolist_management.todo_items i USING (
+    SELECT id, row_number() OVER (PARTITION BY series_id, occurrence ORDER BY completed IS TRUE DESC, id) AS n
+    FROM todolist_management.todo_items WHERE series_id IS NOT NULL
+) d WHERE i.id = d.id AND d.n > 1;
+
+CREATE UNIQUE INDEX todo_items_series_occurrence_idx ON todolist_management.todo_items (series_id, occurrence);
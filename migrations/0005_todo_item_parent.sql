@@ -0,0 +1,4 @@
+-- Sub-items, an item belongs to the same list as its parent
+ALTER TABLE todolist_management.todo_items ADD COLUMN parent_id integer;
+
+CREATE INDEX todo_items_parent_id_idx ON todolist_management.todo_items (parent_id);
@@ -0,0 +1,17 @@
+-- Tags of todo items, shared by all the lists
+CREATE TABLE todolist_management.tags (
+    id serial PRIMARY KEY,
+    name text NOT NULL UNIQUE
+);
+
+ALTER TABLE todolist_management.tags OWNER TO postgres;
+
+CREATE TABLE todolist_management.item_tags (
+    item_id integer NOT NULL REFERENCES todolist_management.todo_items (id) ON DELETE CASCADE,
+    tag_id integer NOT NULL REFERENCES todolist_management.tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (item_id, tag_id)
+);
+
+ALTER TABLE todolist_management.item_tags OWNER TO postgres;
+
+CREATE INDEX item_tags_tag_id_idx ON todolist_management.item_tags (tag_id);
@@ -0,0 +1,4 @@
+-- Timestamps of todo lists
+ALTER TABLE todolist_management.todo_lists
+    ADD COLUMN created_at timestamp with time zone NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at timestamp with time zone NOT NULL DEFAULT now();
@@ -0,0 +1,2 @@
+-- Archived todo lists
+ALTER TABLE todolist_management.todo_lists ADD COLUMN archived boolean NOT NULL DEFAULT false;
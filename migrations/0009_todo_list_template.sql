@@ -0,0 +1,2 @@
+-- Todo lists used as templates of other lists
+ALTER TABLE todolist_management.todo_lists ADD COLUMN template boolean NOT NULL DEFAULT false;
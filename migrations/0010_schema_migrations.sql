@@ -0,0 +1,17 @@
+-- Versions of the migrations applied to the database, the ones before this
+-- one are recorded as applied since they must have been applied in order
+CREATE TABLE public.schema_migrations (
+    version text PRIMARY KEY,
+    applied_at timestamp with time zone NOT NULL DEFAULT now()
+);
+INSERT INTO public.schema_migrations (version) VALUES
+    ('0001_todo_item_position'),
+    ('0002_todo_item_schedule'),
+    ('0003_todo_item_recurrence'),
+    ('0004_todo_item_occurrence'),
+    ('0005_todo_item_parent'),
+    ('0006_tags'),
+    ('0007_todo_list_timestamps'),
+    ('0008_todo_list_archived'),
+    ('0009_todo_list_template'),
+    ('0010_schema_migrations');
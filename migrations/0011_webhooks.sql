@@ -0,0 +1,34 @@
+-- Webhook subscriptions, the outbox of the events of both the services and
+-- the deliveries of the events to the subscriptions
+CREATE SCHEMA webhooks;
+
+CREATE TABLE webhooks.subscriptions (
+    id bigserial PRIMARY KEY,
+    url text NOT NULL,
+    events text[] NOT NULL,
+    secret text NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE TABLE webhooks.outbox (
+    id bigserial PRIMARY KEY,
+    type text NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE TABLE webhooks.deliveries (
+    id bigserial PRIMARY KEY,
+    subscription_id bigint NOT NULL REFERENCES webhooks.subscriptions (id) ON DELETE CASCADE,
+    event_id bigint NOT NULL REFERENCES webhooks.outbox (id) ON DELETE CASCADE,
+    status text NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NOT NULL DEFAULT now(),
+    response_status integer,
+    error text,
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX deliveries_pending ON webhooks.deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX deliveries_subscription ON webhooks.deliveries (subscription_id, id);
+
+INSERT INTO public.schema_migrations (version) VALUES ('0011_webhooks');
@@ -0,0 +1,6 @@
+-- Pruning of the outbox, by the time of the events and then of their
+-- deliveries, deleted along with them
+CREATE INDEX outbox_created_at ON webhooks.outbox (created_at);
+CREATE INDEX deliveries_event ON webhooks.deliveries (event_id);
+
+INSERT INTO public.schema_migrations (version) VALUES ('0012_webhooks_retention');
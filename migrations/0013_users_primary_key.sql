@@ -0,0 +1,13 @@
+-- Primary key of the users, which the owners of the lists and the change log
+-- refer to by their id; a user sharing its id with another one, or without
+-- an id, is given a new one first
+SELECT setval('public.users_id_seq', MAX(id)) FROM user_management.users
+HAVING MAX(id) > (SELECT last_value FROM public.users_id_seq);
+
+UPDATE user_management.users u SET id = nextval('public.users_id_seq')
+FROM (SELECT ctid, id, ROW_NUMBER() OVER (PARTITION BY id ORDER BY ctid) AS n FROM user_management.users) d
+WHERE u.ctid = d.ctid AND (d.id IS NULL OR d.n > 1);
+
+ALTER TABLE user_management.users ADD PRIMARY KEY (id);
+
+INSERT INTO public.schema_migrations (version) VALUES ('0013_users_primary_key');
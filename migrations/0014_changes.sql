@@ -0,0 +1,44 @@
+-- Change log of the lists, items and users, written by the services in the
+-- transaction of every change: a transaction logs the rows it changed as it
+-- commits, the version of a change numbering the changes of its entity, and
+-- numbers its changes last, under a lock held only until it commits, so that
+-- seq orders the changes by their commits and is the cursor of the feed
+CREATE SCHEMA changes;
+
+-- seq is null until the changes of a transaction are numbered
+CREATE TABLE changes.events (
+    id bigserial PRIMARY KEY,
+    seq bigint UNIQUE,
+    entity text NOT NULL,
+    entity_id bigint NOT NULL,
+    version bigint NOT NULL,
+    op text NOT NULL,
+    before jsonb,
+    after jsonb,
+    at timestamp with time zone NOT NULL DEFAULT now(),
+    UNIQUE (entity, entity_id, version)
+);
+CREATE SEQUENCE changes.events_seq OWNED BY changes.events.seq;
+
+-- rows are the logged rows of the lists, items and users; an item is logged
+-- with the names of its tags, the tags not being entities of the log, and
+-- without the time its reminder was sent
+CREATE VIEW changes.rows AS
+SELECT 'todo_list'::text AS entity, l.id::bigint AS id, to_jsonb(l) AS data
+FROM todolist_management.todo_lists l
+UNION ALL
+SELECT 'todo_item', i.id, (to_jsonb(i) - 'reminded_at') || jsonb_build_object('tags', COALESCE((
+    SELECT jsonb_agg(t.name ORDER BY t.name) FROM todolist_management.item_tags it
+    JOIN todolist_management.tags t ON t.id = it.tag_id WHERE it.item_id = i.id), '[]'::jsonb))
+FROM todolist_management.todo_items i
+UNION ALL
+SELECT 'user', u.id, to_jsonb(u)
+FROM user_management.users u;
+
+-- the rows existing before the log are logged once, so that the log holds
+-- the state of every row
+INSERT INTO changes.events (seq, entity, entity_id, version, op, after)
+SELECT nextval('changes.events_seq'), entity, id, 1, 'insert', data
+FROM (SELECT entity, id, data FROM changes.rows ORDER BY entity, id) r;
+
+INSERT INTO public.schema_migrations (version) VALUES ('0014_changes');
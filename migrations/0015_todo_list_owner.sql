@@ -0,0 +1,8 @@
+-- Owners of the todo lists, users of the user-management service; the lists
+-- of a deleted user are left without an owner
+ALTER TABLE todolist_management.todo_lists
+    ADD COLUMN owner_id integer REFERENCES user_management.users (id) ON DELETE SET NULL;
+
+CREATE INDEX todo_lists_owner_id_idx ON todolist_management.todo_lists (owner_id);
+
+INSERT INTO public.schema_migrations (version) VALUES ('0015_todo_list_owner');
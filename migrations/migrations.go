@@ -0,0 +1,64 @@
+// Package migrations lists the schema changes made since database.sql, every
+// migration inserts its version, its file name without .sql, into
+// public.schema_migrations once applied
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"sort"
+	"strings"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Versions returns the versions of all the migrations, in order
+func Versions() []string {
+	entries, _ := files.ReadDir(".")
+	versions := make([]string, 0, len(entries))
+	for _, e := range entries {
+		versions = append(versions, strings.TrimSuffix(e.Name(), ".sql"))
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// Pending returns the versions of the migrations not applied to db yet, all
+// of them until the schema_migrations table is created
+func Pending(ctx context.Context, db *sql.DB) ([]string, error) {
+	versions := Versions()
+	exists := false
+	if err := db.QueryRowContext(ctx, `SELECT to_regclass('public.schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return versions, nil
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT version FROM public.schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := map[string]bool{}
+	for rows.Next() {
+		version := ""
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pending := []string{}
+	for _, version := range versions {
+		if !applied[version] {
+			pending = append(pending, version)
+		}
+	}
+	return pending, nil
+}
@@ -0,0 +1,92 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+)
+
+// TestMigrations applies database.sql and then all the migrations to a new
+// database of the server of TEST_DATABASE_URL, skipped when it's not set
+func TestMigrations(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	server, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	name := fmt.Sprintf("migrations_test_%d", os.Getpid())
+	if _, err := server.ExecContext(ctx, `CREATE DATABASE `+name); err != nil {
+		t.Fatal(err)
+	}
+	defer server.ExecContext(ctx, `DROP DATABASE `+name)
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.Path = "/" + name
+	// every file is run on a connection of its own, as by psql, database.sql
+	// clearing the search_path of its connection
+	run := func(name, query string) {
+		db, err := sql.Open("postgres", u.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+	schema, err := os.ReadFile("../database.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	run("database.sql", string(schema))
+	// users sharing an id, which the migration to their primary key renumbers
+	run("users", `INSERT INTO user_management.users (fname, email, phone_no, id) VALUES
+		('a', 'a@example.com', 1, 1), ('b', 'b@example.com', 2, 1), ('c', 'c@example.com', 3, 40)`)
+	for _, version := range Versions() {
+		query, err := files.ReadFile(version + ".sql")
+		if err != nil {
+			t.Fatal(err)
+		}
+		run(version, string(query))
+	}
+
+	db, err := sql.Open("postgres", u.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	pending, err := Pending(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("pending = %v, want every migration recorded as applied", pending)
+	}
+	ids := 0
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(DISTINCT id) FROM user_management.users WHERE id > 40`).Scan(&ids); err != nil {
+		t.Fatal(err)
+	}
+	if ids != 1 {
+		t.Errorf("%d users renumbered past the ids in use, want 1", ids)
+	}
+	logged := 0
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM changes.events WHERE entity = 'user' AND version = 1`).Scan(&logged); err != nil {
+		t.Fatal(err)
+	}
+	if logged != 3 {
+		t.Errorf("%d users logged, want 3", logged)
+	}
+}
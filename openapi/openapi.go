@@ -0,0 +1,150 @@
+// Package openapi serves the OpenAPI document of a service and its docs,
+// and checks the routes of the service against the paths of the document,
+// so that the document is kept in step with the routes
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	swaggerfiles "github.com/swaggo/files/v2"
+
+	"github.com/Shivam010/go-rest-api/httperr"
+)
+
+// docs is the page of the docs UI, reading the document at openapi.json and
+// the files of Swagger UI served by Assets, none being fetched from a CDN
+//
+//go:embed docs.html
+var docs []byte
+
+// Mux is a ServeMux recording the patterns of its routes
+type Mux struct {
+	*http.ServeMux
+	mu       sync.Mutex
+	patterns []string
+}
+
+// NewMux ...
+func NewMux() *Mux {
+	return &Mux{ServeMux: http.NewServeMux()}
+}
+
+// Handle registers the handler of a pattern
+func (m *Mux) Handle(pattern string, handler http.Handler) {
+	m.record(pattern)
+	m.ServeMux.Handle(pattern, handler)
+}
+
+// HandleFunc registers the handler function of a pattern
+func (m *Mux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.record(pattern)
+	m.ServeMux.HandleFunc(pattern, handler)
+}
+
+func (m *Mux) record(pattern string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.patterns = append(m.patterns, pattern)
+}
+
+// Patterns returns the patterns of the routes, in the order registered
+func (m *Mux) Patterns() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.patterns...)
+}
+
+// Diff returns the paths of the patterns which are not paths of the
+// document spec, and the paths of the document which are the path of none
+// of the patterns, both sorted
+func Diff(spec []byte, patterns []string) (missing, unrouted []string, err error) {
+	doc := struct {
+		Paths map[string]json.RawMessage `json:"paths"`
+	}{}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, nil, err
+	}
+	missing, unrouted = []string{}, []string{}
+	routed := map[string]bool{}
+	for _, pattern := range patterns {
+		path := Path(pattern)
+		routed[path] = true
+		if _, ok := doc.Paths[path]; !ok {
+			missing = append(missing, path)
+		}
+	}
+	for path := range doc.Paths {
+		if !routed[path] {
+			unrouted = append(unrouted, path)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(unrouted)
+	return missing, unrouted, nil
+}
+
+// Check returns an error listing the routes of the patterns missing from the
+// document spec and the paths of the document which are not routed, nil
+// when the document is in step with the routes
+func Check(spec []byte, patterns []string) error {
+	missing, unrouted, err := Diff(spec, patterns)
+	if err != nil {
+		return err
+	}
+	if len(missing) == 0 && len(unrouted) == 0 {
+		return nil
+	}
+	return fmt.Errorf("openapi document out of step with the routes: routes %v missing from the document, paths %v not routed", missing, unrouted)
+}
+
+// Path returns the OpenAPI path of a ServeMux pattern, without its method
+// and host, "/lists/{id}" for "GET example.com/lists/{id}"
+func Path(pattern string) string {
+	if i := strings.IndexAny(pattern, " \t"); i >= 0 {
+		pattern = strings.TrimLeft(pattern[i:], " \t")
+	}
+	if i := strings.IndexByte(pattern, '/'); i > 0 {
+		pattern = pattern[i:]
+	}
+	pattern = strings.TrimSuffix(pattern, "{$}")
+	return strings.ReplaceAll(pattern, "...}", "}")
+}
+
+// Spec returns the handler serving the document spec
+func Spec(spec []byte) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			httperr.Error(w, "404 not found.", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	}
+}
+
+// Assets serves the {file} of Swagger UI, embedded in the service, which the
+// docs UI reads at /docs/{file}
+func Assets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	http.ServeFileFS(w, r, swaggerfiles.FS, r.PathValue("file"))
+}
+
+// Docs serves the docs UI of the document served at /openapi.json
+func Docs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(docs)
+}
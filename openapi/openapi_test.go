@@ -0,0 +1,84 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestDocsAssets(t *testing.T) {
+	mux := NewMux()
+	mux.HandleFunc("/docs", Docs)
+	mux.HandleFunc("/docs/{file}", Assets)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/docs", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /docs = %d", w.Code)
+	}
+	// the page only reads the files of the service
+	refs := regexp.MustCompile(`(?:src|href)="([^"]+)"`).FindAllStringSubmatch(w.Body.String(), -1)
+	if len(refs) == 0 {
+		t.Fatal("the docs page reads no file")
+	}
+	for _, ref := range refs {
+		if strings.Contains(ref[1], "//") {
+			t.Errorf("the docs page reads %s, which isn't served by the service", ref[1])
+			continue
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/"+ref[1], nil))
+		if w.Code != http.StatusOK || w.Body.Len() == 0 {
+			t.Errorf("GET /%s = %d, %d bytes", ref[1], w.Code, w.Body.Len())
+		}
+	}
+
+	tests := []struct {
+		method, target string
+		status         int
+	}{
+		{"GET", "/docs/swagger-ui-bundle.js", http.StatusOK},
+		{"GET", "/docs/missing.js", http.StatusNotFound},
+		{"POST", "/docs/swagger-ui.css", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s %s = %d, want %d", tt.method, tt.target, w.Code, tt.status)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	spec := []byte(`{"paths": {"/lists": {}, "/lists/{id}": {}, "/files/{path}": {}, "/users": {}}}`)
+	missing, unrouted, err := Diff(spec, []string{"/lists", "GET /lists/{id}", "/files/{path...}", "/{$}", "/items"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(missing, ",") != "/,/items" {
+		t.Errorf("Diff() missing = %v, want [/ /items]", missing)
+	}
+	if strings.Join(unrouted, ",") != "/users" {
+		t.Errorf("Diff() unrouted = %v, want [/users]", unrouted)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	spec := []byte(`{"paths": {"/lists": {}, "/lists/{id}": {}}}`)
+	tests := []struct {
+		patterns []string
+		wantErr  bool
+	}{
+		{[]string{"/lists", "GET /lists/{id}"}, false},
+		{[]string{"/lists", "GET /lists/{id}", "/items"}, true},
+		{[]string{"/lists"}, true},
+	}
+	for _, tt := range tests {
+		if err := Check(spec, tt.patterns); (err != nil) != tt.wantErr {
+			t.Errorf("Check(%v) = %v", tt.patterns, err)
+		}
+	}
+}
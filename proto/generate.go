@@ -0,0 +1,6 @@
+// Package proto holds the protobuf definitions of the gRPC APIs of the
+// services, the Go packages of which are generated with protoc,
+// protoc-gen-go and protoc-gen-go-grpc by running go generate
+package proto
+
+//go:generate protoc --go_out=. --go_opt=module=github.com/Shivam010/go-rest-api/proto --go-grpc_out=. --go-grpc_opt=module=github.com/Shivam010/go-rest-api/proto todolist.proto users.proto
@@ -0,0 +1,1972 @@
+// gRPC API of the todolist-management service, over the same core as its
+// JSON endpoints
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.28.3
+// source: todolist.proto
+
+package todolistpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TodoItem struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ListId    int64                  `protobuf:"varint,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	Value     string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Completed bool                   `protobuf:"varint,4,opt,name=completed,proto3" json:"completed,omitempty"`
+	Position  int64                  `protobuf:"varint,5,opt,name=position,proto3" json:"position,omitempty"`
+	DueAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	// low, normal, high or urgent
+	Priority    string                 `protobuf:"bytes,7,opt,name=priority,proto3" json:"priority,omitempty"`
+	CompletedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// RRULE subset: FREQ=DAILY|WEEKLY|MONTHLY;INTERVAL;BYDAY;COUNT;UNTIL
+	Recurrence    string      `protobuf:"bytes,11,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	SeriesId      int64       `protobuf:"varint,12,opt,name=series_id,json=seriesId,proto3" json:"series_id,omitempty"`
+	Occurrence    int32       `protobuf:"varint,13,opt,name=occurrence,proto3" json:"occurrence,omitempty"`
+	ParentId      int64       `protobuf:"varint,14,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Children      []*TodoItem `protobuf:"bytes,15,rep,name=children,proto3" json:"children,omitempty"`
+	Tags          []string    `protobuf:"bytes,16,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TodoItem) Reset() {
+	*x = TodoItem{}
+	mi := &file_todolist_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TodoItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TodoItem) ProtoMessage() {}
+
+func (x *TodoItem) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TodoItem.ProtoReflect.Descriptor instead.
+func (*TodoItem) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TodoItem) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TodoItem) GetListId() int64 {
+	if x != nil {
+		return x.ListId
+	}
+	return 0
+}
+
+func (x *TodoItem) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *TodoItem) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+func (x *TodoItem) GetPosition() int64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *TodoItem) GetDueAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueAt
+	}
+	return nil
+}
+
+func (x *TodoItem) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *TodoItem) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *TodoItem) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *TodoItem) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *TodoItem) GetRecurrence() string {
+	if x != nil {
+		return x.Recurrence
+	}
+	return ""
+}
+
+func (x *TodoItem) GetSeriesId() int64 {
+	if x != nil {
+		return x.SeriesId
+	}
+	return 0
+}
+
+func (x *TodoItem) GetOccurrence() int32 {
+	if x != nil {
+		return x.Occurrence
+	}
+	return 0
+}
+
+func (x *TodoItem) GetParentId() int64 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *TodoItem) GetChildren() []*TodoItem {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+func (x *TodoItem) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// TodoList is a list, without its items in the listings
+type TodoList struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Items          []*TodoItem            `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Archived       bool                   `protobuf:"varint,4,opt,name=archived,proto3" json:"archived,omitempty"`
+	Template       bool                   `protobuf:"varint,5,opt,name=template,proto3" json:"template,omitempty"`
+	ItemCount      int32                  `protobuf:"varint,6,opt,name=item_count,json=itemCount,proto3" json:"item_count,omitempty"`
+	CompletedCount int32                  `protobuf:"varint,7,opt,name=completed_count,json=completedCount,proto3" json:"completed_count,omitempty"`
+	Completion     float64                `protobuf:"fixed64,8,opt,name=completion,proto3" json:"completion,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TodoList) Reset() {
+	*x = TodoList{}
+	mi := &file_todolist_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TodoList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TodoList) ProtoMessage() {}
+
+func (x *TodoList) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TodoList.ProtoReflect.Descriptor instead.
+func (*TodoList) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TodoList) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TodoList) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TodoList) GetItems() []*TodoItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *TodoList) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+func (x *TodoList) GetTemplate() bool {
+	if x != nil {
+		return x.Template
+	}
+	return false
+}
+
+func (x *TodoList) GetItemCount() int32 {
+	if x != nil {
+		return x.ItemCount
+	}
+	return 0
+}
+
+func (x *TodoList) GetCompletedCount() int32 {
+	if x != nil {
+		return x.CompletedCount
+	}
+	return 0
+}
+
+func (x *TodoList) GetCompletion() float64 {
+	if x != nil {
+		return x.Completion
+	}
+	return 0
+}
+
+func (x *TodoList) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *TodoList) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// TagFilter matches the items having all of its tags or, when any is set,
+// any of them
+type TagFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tags          []string               `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	Any           bool                   `protobuf:"varint,2,opt,name=any,proto3" json:"any,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TagFilter) Reset() {
+	*x = TagFilter{}
+	mi := &file_todolist_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TagFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagFilter) ProtoMessage() {}
+
+func (x *TagFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagFilter.ProtoReflect.Descriptor instead.
+func (*TagFilter) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TagFilter) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *TagFilter) GetAny() bool {
+	if x != nil {
+		return x.Any
+	}
+	return false
+}
+
+// Event is a change of a list, item only being set for the changes of an
+// item made directly
+type Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	ListId        int64                  `protobuf:"varint,3,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	ItemId        int64                  `protobuf:"varint,4,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Item          *TodoItem              `protobuf:"bytes,5,opt,name=item,proto3" json:"item,omitempty"`
+	Name          string                 `protobuf:"bytes,6,opt,name=name,proto3" json:"name,omitempty"`
+	At            *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=at,proto3" json:"at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_todolist_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Event) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetListId() int64 {
+	if x != nil {
+		return x.ListId
+	}
+	return 0
+}
+
+func (x *Event) GetItemId() int64 {
+	if x != nil {
+		return x.ItemId
+	}
+	return 0
+}
+
+func (x *Event) GetItem() *TodoItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *Event) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Event) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+type AddTodoListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Template      bool                   `protobuf:"varint,2,opt,name=template,proto3" json:"template,omitempty"`
+	Items         []*TodoItem            `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTodoListRequest) Reset() {
+	*x = AddTodoListRequest{}
+	mi := &file_todolist_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTodoListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTodoListRequest) ProtoMessage() {}
+
+func (x *AddTodoListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTodoListRequest.ProtoReflect.Descriptor instead.
+func (*AddTodoListRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AddTodoListRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AddTodoListRequest) GetTemplate() bool {
+	if x != nil {
+		return x.Template
+	}
+	return false
+}
+
+func (x *AddTodoListRequest) GetItems() []*TodoItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type GetTodoListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Filter        *TagFilter             `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTodoListRequest) Reset() {
+	*x = GetTodoListRequest{}
+	mi := &file_todolist_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTodoListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTodoListRequest) ProtoMessage() {}
+
+func (x *GetTodoListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTodoListRequest.ProtoReflect.Descriptor instead.
+func (*GetTodoListRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetTodoListRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetTodoListRequest) GetFilter() *TagFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type ListTodoListsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 20 by default, at most 100
+	Limit         int32  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Sort          string `protobuf:"bytes,3,opt,name=sort,proto3" json:"sort,omitempty"`
+	Desc          bool   `protobuf:"varint,4,opt,name=desc,proto3" json:"desc,omitempty"`
+	Archived      bool   `protobuf:"varint,5,opt,name=archived,proto3" json:"archived,omitempty"`
+	Templates     bool   `protobuf:"varint,6,opt,name=templates,proto3" json:"templates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTodoListsRequest) Reset() {
+	*x = ListTodoListsRequest{}
+	mi := &file_todolist_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTodoListsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTodoListsRequest) ProtoMessage() {}
+
+func (x *ListTodoListsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTodoListsRequest.ProtoReflect.Descriptor instead.
+func (*ListTodoListsRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListTodoListsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListTodoListsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListTodoListsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListTodoListsRequest) GetDesc() bool {
+	if x != nil {
+		return x.Desc
+	}
+	return false
+}
+
+func (x *ListTodoListsRequest) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+func (x *ListTodoListsRequest) GetTemplates() bool {
+	if x != nil {
+		return x.Templates
+	}
+	return false
+}
+
+type ListTodoListsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lists         []*TodoList            `protobuf:"bytes,1,rep,name=lists,proto3" json:"lists,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTodoListsResponse) Reset() {
+	*x = ListTodoListsResponse{}
+	mi := &file_todolist_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTodoListsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTodoListsResponse) ProtoMessage() {}
+
+func (x *ListTodoListsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTodoListsResponse.ProtoReflect.Descriptor instead.
+func (*ListTodoListsResponse) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListTodoListsResponse) GetLists() []*TodoList {
+	if x != nil {
+		return x.Lists
+	}
+	return nil
+}
+
+func (x *ListTodoListsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListTodoListsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListTodoListsResponse) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type EditTodoListNameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditTodoListNameRequest) Reset() {
+	*x = EditTodoListNameRequest{}
+	mi := &file_todolist_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditTodoListNameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditTodoListNameRequest) ProtoMessage() {}
+
+func (x *EditTodoListNameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditTodoListNameRequest.ProtoReflect.Descriptor instead.
+func (*EditTodoListNameRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *EditTodoListNameRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EditTodoListNameRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteTodoListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTodoListRequest) Reset() {
+	*x = DeleteTodoListRequest{}
+	mi := &file_todolist_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTodoListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTodoListRequest) ProtoMessage() {}
+
+func (x *DeleteTodoListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTodoListRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTodoListRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteTodoListRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ArchiveTodoListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveTodoListRequest) Reset() {
+	*x = ArchiveTodoListRequest{}
+	mi := &file_todolist_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveTodoListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveTodoListRequest) ProtoMessage() {}
+
+func (x *ArchiveTodoListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveTodoListRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveTodoListRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ArchiveTodoListRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type UnarchiveTodoListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnarchiveTodoListRequest) Reset() {
+	*x = UnarchiveTodoListRequest{}
+	mi := &file_todolist_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnarchiveTodoListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnarchiveTodoListRequest) ProtoMessage() {}
+
+func (x *UnarchiveTodoListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnarchiveTodoListRequest.ProtoReflect.Descriptor instead.
+func (*UnarchiveTodoListRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UnarchiveTodoListRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CloneTodoListRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ResetCompleted bool                   `protobuf:"varint,3,opt,name=reset_completed,json=resetCompleted,proto3" json:"reset_completed,omitempty"`
+	Template       bool                   `protobuf:"varint,4,opt,name=template,proto3" json:"template,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CloneTodoListRequest) Reset() {
+	*x = CloneTodoListRequest{}
+	mi := &file_todolist_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloneTodoListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloneTodoListRequest) ProtoMessage() {}
+
+func (x *CloneTodoListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloneTodoListRequest.ProtoReflect.Descriptor instead.
+func (*CloneTodoListRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CloneTodoListRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CloneTodoListRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CloneTodoListRequest) GetResetCompleted() bool {
+	if x != nil {
+		return x.ResetCompleted
+	}
+	return false
+}
+
+func (x *CloneTodoListRequest) GetTemplate() bool {
+	if x != nil {
+		return x.Template
+	}
+	return false
+}
+
+type InstantiateTemplateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Variables     map[string]string      `protobuf:"bytes,3,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstantiateTemplateRequest) Reset() {
+	*x = InstantiateTemplateRequest{}
+	mi := &file_todolist_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstantiateTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstantiateTemplateRequest) ProtoMessage() {}
+
+func (x *InstantiateTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstantiateTemplateRequest.ProtoReflect.Descriptor instead.
+func (*InstantiateTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *InstantiateTemplateRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *InstantiateTemplateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *InstantiateTemplateRequest) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+type WatchTodoListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	LastEventId   string                 `protobuf:"bytes,2,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTodoListRequest) Reset() {
+	*x = WatchTodoListRequest{}
+	mi := &file_todolist_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTodoListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTodoListRequest) ProtoMessage() {}
+
+func (x *WatchTodoListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTodoListRequest.ProtoReflect.Descriptor instead.
+func (*WatchTodoListRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WatchTodoListRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WatchTodoListRequest) GetLastEventId() string {
+	if x != nil {
+		return x.LastEventId
+	}
+	return ""
+}
+
+type AddTodoItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        int64                  `protobuf:"varint,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	Item          *TodoItem              `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTodoItemRequest) Reset() {
+	*x = AddTodoItemRequest{}
+	mi := &file_todolist_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTodoItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTodoItemRequest) ProtoMessage() {}
+
+func (x *AddTodoItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTodoItemRequest.ProtoReflect.Descriptor instead.
+func (*AddTodoItemRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AddTodoItemRequest) GetListId() int64 {
+	if x != nil {
+		return x.ListId
+	}
+	return 0
+}
+
+func (x *AddTodoItemRequest) GetItem() *TodoItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+type GetTodoItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTodoItemRequest) Reset() {
+	*x = GetTodoItemRequest{}
+	mi := &file_todolist_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTodoItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTodoItemRequest) ProtoMessage() {}
+
+func (x *GetTodoItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTodoItemRequest.ProtoReflect.Descriptor instead.
+func (*GetTodoItemRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetTodoItemRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type UpdateTodoItemRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Item  *TodoItem              `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	// value, completed, due_at, priority and recurrence, all of them when empty
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTodoItemRequest) Reset() {
+	*x = UpdateTodoItemRequest{}
+	mi := &file_todolist_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTodoItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTodoItemRequest) ProtoMessage() {}
+
+func (x *UpdateTodoItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTodoItemRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTodoItemRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UpdateTodoItemRequest) GetItem() *TodoItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *UpdateTodoItemRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type UpdateTodoSeriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SeriesId      int64                  `protobuf:"varint,1,opt,name=series_id,json=seriesId,proto3" json:"series_id,omitempty"`
+	Item          *TodoItem              `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTodoSeriesRequest) Reset() {
+	*x = UpdateTodoSeriesRequest{}
+	mi := &file_todolist_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTodoSeriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTodoSeriesRequest) ProtoMessage() {}
+
+func (x *UpdateTodoSeriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTodoSeriesRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTodoSeriesRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UpdateTodoSeriesRequest) GetSeriesId() int64 {
+	if x != nil {
+		return x.SeriesId
+	}
+	return 0
+}
+
+func (x *UpdateTodoSeriesRequest) GetItem() *TodoItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+type DeleteTodoItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Cascade       bool                   `protobuf:"varint,2,opt,name=cascade,proto3" json:"cascade,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTodoItemRequest) Reset() {
+	*x = DeleteTodoItemRequest{}
+	mi := &file_todolist_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTodoItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTodoItemRequest) ProtoMessage() {}
+
+func (x *DeleteTodoItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTodoItemRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTodoItemRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteTodoItemRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteTodoItemRequest) GetCascade() bool {
+	if x != nil {
+		return x.Cascade
+	}
+	return false
+}
+
+type MoveTodoItemRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ListId int64                  `protobuf:"varint,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	Before int64                  `protobuf:"varint,3,opt,name=before,proto3" json:"before,omitempty"`
+	After  int64                  `protobuf:"varint,4,opt,name=after,proto3" json:"after,omitempty"`
+	// 0 moves the item to the top level
+	ParentId      *int64 `protobuf:"varint,5,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveTodoItemRequest) Reset() {
+	*x = MoveTodoItemRequest{}
+	mi := &file_todolist_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveTodoItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveTodoItemRequest) ProtoMessage() {}
+
+func (x *MoveTodoItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveTodoItemRequest.ProtoReflect.Descriptor instead.
+func (*MoveTodoItemRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *MoveTodoItemRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *MoveTodoItemRequest) GetListId() int64 {
+	if x != nil {
+		return x.ListId
+	}
+	return 0
+}
+
+func (x *MoveTodoItemRequest) GetBefore() int64 {
+	if x != nil {
+		return x.Before
+	}
+	return 0
+}
+
+func (x *MoveTodoItemRequest) GetAfter() int64 {
+	if x != nil {
+		return x.After
+	}
+	return 0
+}
+
+func (x *MoveTodoItemRequest) GetParentId() int64 {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return 0
+}
+
+type TodoItemTagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Tags          []string               `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TodoItemTagsRequest) Reset() {
+	*x = TodoItemTagsRequest{}
+	mi := &file_todolist_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TodoItemTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TodoItemTagsRequest) ProtoMessage() {}
+
+func (x *TodoItemTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TodoItemTagsRequest.ProtoReflect.Descriptor instead.
+func (*TodoItemTagsRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TodoItemTagsRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TodoItemTagsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type GetItemsByTagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *TagFilter             `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Archived      bool                   `protobuf:"varint,2,opt,name=archived,proto3" json:"archived,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetItemsByTagsRequest) Reset() {
+	*x = GetItemsByTagsRequest{}
+	mi := &file_todolist_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetItemsByTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetItemsByTagsRequest) ProtoMessage() {}
+
+func (x *GetItemsByTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetItemsByTagsRequest.ProtoReflect.Descriptor instead.
+func (*GetItemsByTagsRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetItemsByTagsRequest) GetFilter() *TagFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *GetItemsByTagsRequest) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+type GetOverdueItemsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Archived      bool                   `protobuf:"varint,1,opt,name=archived,proto3" json:"archived,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOverdueItemsRequest) Reset() {
+	*x = GetOverdueItemsRequest{}
+	mi := &file_todolist_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOverdueItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOverdueItemsRequest) ProtoMessage() {}
+
+func (x *GetOverdueItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOverdueItemsRequest.ProtoReflect.Descriptor instead.
+func (*GetOverdueItemsRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetOverdueItemsRequest) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+type GetItemsDueBetweenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Archived      bool                   `protobuf:"varint,3,opt,name=archived,proto3" json:"archived,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetItemsDueBetweenRequest) Reset() {
+	*x = GetItemsDueBetweenRequest{}
+	mi := &file_todolist_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetItemsDueBetweenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetItemsDueBetweenRequest) ProtoMessage() {}
+
+func (x *GetItemsDueBetweenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetItemsDueBetweenRequest.ProtoReflect.Descriptor instead.
+func (*GetItemsDueBetweenRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetItemsDueBetweenRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetItemsDueBetweenRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *GetItemsDueBetweenRequest) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+type TodoItems struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*TodoItem            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TodoItems) Reset() {
+	*x = TodoItems{}
+	mi := &file_todolist_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TodoItems) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TodoItems) ProtoMessage() {}
+
+func (x *TodoItems) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TodoItems.ProtoReflect.Descriptor instead.
+func (*TodoItems) Descriptor() ([]byte, []int) {
+	return file_todolist_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *TodoItems) GetItems() []*TodoItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+var File_todolist_proto protoreflect.FileDescriptor
+
+const file_todolist_proto_rawDesc = "" +
+	"\n" +
+	"\x0etodolist.proto\x12\vtodolist.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc8\x04\n" +
+	"\bTodoItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\alist_id\x18\x02 \x01(\x03R\x06listId\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x1c\n" +
+	"\tcompleted\x18\x04 \x01(\bR\tcompleted\x12\x1a\n" +
+	"\bposition\x18\x05 \x01(\x03R\bposition\x121\n" +
+	"\x06due_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x05dueAt\x12\x1a\n" +
+	"\bpriority\x18\a \x01(\tR\bpriority\x12=\n" +
+	"\fcompleted_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1e\n" +
+	"\n" +
+	"recurrence\x18\v \x01(\tR\n" +
+	"recurrence\x12\x1b\n" +
+	"\tseries_id\x18\f \x01(\x03R\bseriesId\x12\x1e\n" +
+	"\n" +
+	"occurrence\x18\r \x01(\x05R\n" +
+	"occurrence\x12\x1b\n" +
+	"\tparent_id\x18\x0e \x01(\x03R\bparentId\x121\n" +
+	"\bchildren\x18\x0f \x03(\v2\x15.todolist.v1.TodoItemR\bchildren\x12\x12\n" +
+	"\x04tags\x18\x10 \x03(\tR\x04tags\"\xf1\x02\n" +
+	"\bTodoList\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12+\n" +
+	"\x05items\x18\x03 \x03(\v2\x15.todolist.v1.TodoItemR\x05items\x12\x1a\n" +
+	"\barchived\x18\x04 \x01(\bR\barchived\x12\x1a\n" +
+	"\btemplate\x18\x05 \x01(\bR\btemplate\x12\x1d\n" +
+	"\n" +
+	"item_count\x18\x06 \x01(\x05R\titemCount\x12'\n" +
+	"\x0fcompleted_count\x18\a \x01(\x05R\x0ecompletedCount\x12\x1e\n" +
+	"\n" +
+	"completion\x18\b \x01(\x01R\n" +
+	"completion\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"1\n" +
+	"\tTagFilter\x12\x12\n" +
+	"\x04tags\x18\x01 \x03(\tR\x04tags\x12\x10\n" +
+	"\x03any\x18\x02 \x01(\bR\x03any\"\xc8\x01\n" +
+	"\x05Event\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x17\n" +
+	"\alist_id\x18\x03 \x01(\x03R\x06listId\x12\x17\n" +
+	"\aitem_id\x18\x04 \x01(\x03R\x06itemId\x12)\n" +
+	"\x04item\x18\x05 \x01(\v2\x15.todolist.v1.TodoItemR\x04item\x12\x12\n" +
+	"\x04name\x18\x06 \x01(\tR\x04name\x12*\n" +
+	"\x02at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x02at\"q\n" +
+	"\x12AddTodoListRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\btemplate\x18\x02 \x01(\bR\btemplate\x12+\n" +
+	"\x05items\x18\x03 \x03(\v2\x15.todolist.v1.TodoItemR\x05items\"T\n" +
+	"\x12GetTodoListRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12.\n" +
+	"\x06filter\x18\x02 \x01(\v2\x16.todolist.v1.TagFilterR\x06filter\"\xa6\x01\n" +
+	"\x14ListTodoListsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x12\n" +
+	"\x04sort\x18\x03 \x01(\tR\x04sort\x12\x12\n" +
+	"\x04desc\x18\x04 \x01(\bR\x04desc\x12\x1a\n" +
+	"\barchived\x18\x05 \x01(\bR\barchived\x12\x1c\n" +
+	"\ttemplates\x18\x06 \x01(\bR\ttemplates\"\x88\x01\n" +
+	"\x15ListTodoListsResponse\x12+\n" +
+	"\x05lists\x18\x01 \x03(\v2\x15.todolist.v1.TodoListR\x05lists\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"=\n" +
+	"\x17EditTodoListNameRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"'\n" +
+	"\x15DeleteTodoListRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"(\n" +
+	"\x16ArchiveTodoListRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"*\n" +
+	"\x18UnarchiveTodoListRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x7f\n" +
+	"\x14CloneTodoListRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12'\n" +
+	"\x0freset_completed\x18\x03 \x01(\bR\x0eresetCompleted\x12\x1a\n" +
+	"\btemplate\x18\x04 \x01(\bR\btemplate\"\xd4\x01\n" +
+	"\x1aInstantiateTemplateRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12T\n" +
+	"\tvariables\x18\x03 \x03(\v26.todolist.v1.InstantiateTemplateRequest.VariablesEntryR\tvariables\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"J\n" +
+	"\x14WatchTodoListRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\"\n" +
+	"\rlast_event_id\x18\x02 \x01(\tR\vlastEventId\"X\n" +
+	"\x12AddTodoItemRequest\x12\x17\n" +
+	"\alist_id\x18\x01 \x01(\x03R\x06listId\x12)\n" +
+	"\x04item\x18\x02 \x01(\v2\x15.todolist.v1.TodoItemR\x04item\"$\n" +
+	"\x12GetTodoItemRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x7f\n" +
+	"\x15UpdateTodoItemRequest\x12)\n" +
+	"\x04item\x18\x01 \x01(\v2\x15.todolist.v1.TodoItemR\x04item\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"a\n" +
+	"\x17UpdateTodoSeriesRequest\x12\x1b\n" +
+	"\tseries_id\x18\x01 \x01(\x03R\bseriesId\x12)\n" +
+	"\x04item\x18\x02 \x01(\v2\x15.todolist.v1.TodoItemR\x04item\"A\n" +
+	"\x15DeleteTodoItemRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\acascade\x18\x02 \x01(\bR\acascade\"\x9c\x01\n" +
+	"\x13MoveTodoItemRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\alist_id\x18\x02 \x01(\x03R\x06listId\x12\x16\n" +
+	"\x06before\x18\x03 \x01(\x03R\x06before\x12\x14\n" +
+	"\x05after\x18\x04 \x01(\x03R\x05after\x12 \n" +
+	"\tparent_id\x18\x05 \x01(\x03H\x00R\bparentId\x88\x01\x01B\f\n" +
+	"\n" +
+	"_parent_id\"9\n" +
+	"\x13TodoItemTagsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\"c\n" +
+	"\x15GetItemsByTagsRequest\x12.\n" +
+	"\x06filter\x18\x01 \x01(\v2\x16.todolist.v1.TagFilterR\x06filter\x12\x1a\n" +
+	"\barchived\x18\x02 \x01(\bR\barchived\"4\n" +
+	"\x16GetOverdueItemsRequest\x12\x1a\n" +
+	"\barchived\x18\x01 \x01(\bR\barchived\"\x93\x01\n" +
+	"\x19GetItemsDueBetweenRequest\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x1a\n" +
+	"\barchived\x18\x03 \x01(\bR\barchived\"8\n" +
+	"\tTodoItems\x12+\n" +
+	"\x05items\x18\x01 \x03(\v2\x15.todolist.v1.TodoItemR\x05items2\xfb\f\n" +
+	"\x0fTodoListService\x12E\n" +
+	"\vAddTodoList\x12\x1f.todolist.v1.AddTodoListRequest\x1a\x15.todolist.v1.TodoList\x12E\n" +
+	"\vGetTodoList\x12\x1f.todolist.v1.GetTodoListRequest\x1a\x15.todolist.v1.TodoList\x12V\n" +
+	"\rListTodoLists\x12!.todolist.v1.ListTodoListsRequest\x1a\".todolist.v1.ListTodoListsResponse\x12P\n" +
+	"\x10EditTodoListName\x12$.todolist.v1.EditTodoListNameRequest\x1a\x16.google.protobuf.Empty\x12L\n" +
+	"\x0eDeleteTodoList\x12\".todolist.v1.DeleteTodoListRequest\x1a\x16.google.protobuf.Empty\x12N\n" +
+	"\x0fArchiveTodoList\x12#.todolist.v1.ArchiveTodoListRequest\x1a\x16.google.protobuf.Empty\x12R\n" +
+	"\x11UnarchiveTodoList\x12%.todolist.v1.UnarchiveTodoListRequest\x1a\x16.google.protobuf.Empty\x12I\n" +
+	"\rCloneTodoList\x12!.todolist.v1.CloneTodoListRequest\x1a\x15.todolist.v1.TodoList\x12U\n" +
+	"\x13InstantiateTemplate\x12'.todolist.v1.InstantiateTemplateRequest\x1a\x15.todolist.v1.TodoList\x12H\n" +
+	"\rWatchTodoList\x12!.todolist.v1.WatchTodoListRequest\x1a\x12.todolist.v1.Event0\x01\x12E\n" +
+	"\vAddTodoItem\x12\x1f.todolist.v1.AddTodoItemRequest\x1a\x15.todolist.v1.TodoItem\x12E\n" +
+	"\vGetTodoItem\x12\x1f.todolist.v1.GetTodoItemRequest\x1a\x15.todolist.v1.TodoItem\x12L\n" +
+	"\x0eUpdateTodoItem\x12\".todolist.v1.UpdateTodoItemRequest\x1a\x16.google.protobuf.Empty\x12P\n" +
+	"\x10UpdateTodoSeries\x12$.todolist.v1.UpdateTodoSeriesRequest\x1a\x16.google.protobuf.Empty\x12L\n" +
+	"\x0eDeleteTodoItem\x12\".todolist.v1.DeleteTodoItemRequest\x1a\x16.google.protobuf.Empty\x12G\n" +
+	"\fMoveTodoItem\x12 .todolist.v1.MoveTodoItemRequest\x1a\x15.todolist.v1.TodoItem\x12J\n" +
+	"\x0fAddTodoItemTags\x12 .todolist.v1.TodoItemTagsRequest\x1a\x15.todolist.v1.TodoItem\x12M\n" +
+	"\x12RemoveTodoItemTags\x12 .todolist.v1.TodoItemTagsRequest\x1a\x15.todolist.v1.TodoItem\x12L\n" +
+	"\x0eGetItemsByTags\x12\".todolist.v1.GetItemsByTagsRequest\x1a\x16.todolist.v1.TodoItems\x12N\n" +
+	"\x0fGetOverdueItems\x12#.todolist.v1.GetOverdueItemsRequest\x1a\x16.todolist.v1.TodoItems\x12T\n" +
+	"\x12GetItemsDueBetween\x12&.todolist.v1.GetItemsDueBetweenRequest\x1a\x16.todolist.v1.TodoItemsB3Z1github.com/Shivam010/go-rest-api/proto/todolistpbb\x06proto3"
+
+var (
+	file_todolist_proto_rawDescOnce sync.Once
+	file_todolist_proto_rawDescData []byte
+)
+
+func file_todolist_proto_rawDescGZIP() []byte {
+	file_todolist_proto_rawDescOnce.Do(func() {
+		file_todolist_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_todolist_proto_rawDesc), len(file_todolist_proto_rawDesc)))
+	})
+	return file_todolist_proto_rawDescData
+}
+
+var file_todolist_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
+var file_todolist_proto_goTypes = []any{
+	(*TodoItem)(nil),                   // 0: todolist.v1.TodoItem
+	(*TodoList)(nil),                   // 1: todolist.v1.TodoList
+	(*TagFilter)(nil),                  // 2: todolist.v1.TagFilter
+	(*Event)(nil),                      // 3: todolist.v1.Event
+	(*AddTodoListRequest)(nil),         // 4: todolist.v1.AddTodoListRequest
+	(*GetTodoListRequest)(nil),         // 5: todolist.v1.GetTodoListRequest
+	(*ListTodoListsRequest)(nil),       // 6: todolist.v1.ListTodoListsRequest
+	(*ListTodoListsResponse)(nil),      // 7: todolist.v1.ListTodoListsResponse
+	(*EditTodoListNameRequest)(nil),    // 8: todolist.v1.EditTodoListNameRequest
+	(*DeleteTodoListRequest)(nil),      // 9: todolist.v1.DeleteTodoListRequest
+	(*ArchiveTodoListRequest)(nil),     // 10: todolist.v1.ArchiveTodoListRequest
+	(*UnarchiveTodoListRequest)(nil),   // 11: todolist.v1.UnarchiveTodoListRequest
+	(*CloneTodoListRequest)(nil),       // 12: todolist.v1.CloneTodoListRequest
+	(*InstantiateTemplateRequest)(nil), // 13: todolist.v1.InstantiateTemplateRequest
+	(*WatchTodoListRequest)(nil),       // 14: todolist.v1.WatchTodoListRequest
+	(*AddTodoItemRequest)(nil),         // 15: todolist.v1.AddTodoItemRequest
+	(*GetTodoItemRequest)(nil),         // 16: todolist.v1.GetTodoItemRequest
+	(*UpdateTodoItemRequest)(nil),      // 17: todolist.v1.UpdateTodoItemRequest
+	(*UpdateTodoSeriesRequest)(nil),    // 18: todolist.v1.UpdateTodoSeriesRequest
+	(*DeleteTodoItemRequest)(nil),      // 19: todolist.v1.DeleteTodoItemRequest
+	(*MoveTodoItemRequest)(nil),        // 20: todolist.v1.MoveTodoItemRequest
+	(*TodoItemTagsRequest)(nil),        // 21: todolist.v1.TodoItemTagsRequest
+	(*GetItemsByTagsRequest)(nil),      // 22: todolist.v1.GetItemsByTagsRequest
+	(*GetOverdueItemsRequest)(nil),     // 23: todolist.v1.GetOverdueItemsRequest
+	(*GetItemsDueBetweenRequest)(nil),  // 24: todolist.v1.GetItemsDueBetweenRequest
+	(*TodoItems)(nil),                  // 25: todolist.v1.TodoItems
+	nil,                                // 26: todolist.v1.InstantiateTemplateRequest.VariablesEntry
+	(*timestamppb.Timestamp)(nil),      // 27: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),      // 28: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),              // 29: google.protobuf.Empty
+}
+var file_todolist_proto_depIdxs = []int32{
+	27, // 0: todolist.v1.TodoItem.due_at:type_name -> google.protobuf.Timestamp
+	27, // 1: todolist.v1.TodoItem.completed_at:type_name -> google.protobuf.Timestamp
+	27, // 2: todolist.v1.TodoItem.created_at:type_name -> google.protobuf.Timestamp
+	27, // 3: todolist.v1.TodoItem.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 4: todolist.v1.TodoItem.children:type_name -> todolist.v1.TodoItem
+	0,  // 5: todolist.v1.TodoList.items:type_name -> todolist.v1.TodoItem
+	27, // 6: todolist.v1.TodoList.created_at:type_name -> google.protobuf.Timestamp
+	27, // 7: todolist.v1.TodoList.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 8: todolist.v1.Event.item:type_name -> todolist.v1.TodoItem
+	27, // 9: todolist.v1.Event.at:type_name -> google.protobuf.Timestamp
+	0,  // 10: todolist.v1.AddTodoListRequest.items:type_name -> todolist.v1.TodoItem
+	2,  // 11: todolist.v1.GetTodoListRequest.filter:type_name -> todolist.v1.TagFilter
+	1,  // 12: todolist.v1.ListTodoListsResponse.lists:type_name -> todolist.v1.TodoList
+	26, // 13: todolist.v1.InstantiateTemplateRequest.variables:type_name -> todolist.v1.InstantiateTemplateRequest.VariablesEntry
+	0,  // 14: todolist.v1.AddTodoItemRequest.item:type_name -> todolist.v1.TodoItem
+	0,  // 15: todolist.v1.UpdateTodoItemRequest.item:type_name -> todolist.v1.TodoItem
+	28, // 16: todolist.v1.UpdateTodoItemRequest.update_mask:type_name -> google.protobuf.FieldMask
+	0,  // 17: todolist.v1.UpdateTodoSeriesRequest.item:type_name -> todolist.v1.TodoItem
+	2,  // 18: todolist.v1.GetItemsByTagsRequest.filter:type_name -> todolist.v1.TagFilter
+	27, // 19: todolist.v1.GetItemsDueBetweenRequest.from:type_name -> google.protobuf.Timestamp
+	27, // 20: todolist.v1.GetItemsDueBetweenRequest.to:type_name -> google.protobuf.Timestamp
+	0,  // 21: todolist.v1.TodoItems.items:type_name -> todolist.v1.TodoItem
+	4,  // 22: todolist.v1.TodoListService.AddTodoList:input_type -> todolist.v1.AddTodoListRequest
+	5,  // 23: todolist.v1.TodoListService.GetTodoList:input_type -> todolist.v1.GetTodoListRequest
+	6,  // 24: todolist.v1.TodoListService.ListTodoLists:input_type -> todolist.v1.ListTodoListsRequest
+	8,  // 25: todolist.v1.TodoListService.EditTodoListName:input_type -> todolist.v1.EditTodoListNameRequest
+	9,  // 26: todolist.v1.TodoListService.DeleteTodoList:input_type -> todolist.v1.DeleteTodoListRequest
+	10, // 27: todolist.v1.TodoListService.ArchiveTodoList:input_type -> todolist.v1.ArchiveTodoListRequest
+	11, // 28: todolist.v1.TodoListService.UnarchiveTodoList:input_type -> todolist.v1.UnarchiveTodoListRequest
+	12, // 29: todolist.v1.TodoListService.CloneTodoList:input_type -> todolist.v1.CloneTodoListRequest
+	13, // 30: todolist.v1.TodoListService.InstantiateTemplate:input_type -> todolist.v1.InstantiateTemplateRequest
+	14, // 31: todolist.v1.TodoListService.WatchTodoList:input_type -> todolist.v1.WatchTodoListRequest
+	15, // 32: todolist.v1.TodoListService.AddTodoItem:input_type -> todolist.v1.AddTodoItemRequest
+	16, // 33: todolist.v1.TodoListService.GetTodoItem:input_type -> todolist.v1.GetTodoItemRequest
+	17, // 34: todolist.v1.TodoListService.UpdateTodoItem:input_type -> todolist.v1.UpdateTodoItemRequest
+	18, // 35: todolist.v1.TodoListService.UpdateTodoSeries:input_type -> todolist.v1.UpdateTodoSeriesRequest
+	19, // 36: todolist.v1.TodoListService.DeleteTodoItem:input_type -> todolist.v1.DeleteTodoItemRequest
+	20, // 37: todolist.v1.TodoListService.MoveTodoItem:input_type -> todolist.v1.MoveTodoItemRequest
+	21, // 38: todolist.v1.TodoListService.AddTodoItemTags:input_type -> todolist.v1.TodoItemTagsRequest
+	21, // 39: todolist.v1.TodoListService.RemoveTodoItemTags:input_type -> todolist.v1.TodoItemTagsRequest
+	22, // 40: todolist.v1.TodoListService.GetItemsByTags:input_type -> todolist.v1.GetItemsByTagsRequest
+	23, // 41: todolist.v1.TodoListService.GetOverdueItems:input_type -> todolist.v1.GetOverdueItemsRequest
+	24, // 42: todolist.v1.TodoListService.GetItemsDueBetween:input_type -> todolist.v1.GetItemsDueBetweenRequest
+	1,  // 43: todolist.v1.TodoListService.AddTodoList:output_type -> todolist.v1.TodoList
+	1,  // 44: todolist.v1.TodoListService.GetTodoList:output_type -> todolist.v1.TodoList
+	7,  // 45: todolist.v1.TodoListService.ListTodoLists:output_type -> todolist.v1.ListTodoListsResponse
+	29, // 46: todolist.v1.TodoListService.EditTodoListName:output_type -> google.protobuf.Empty
+	29, // 47: todolist.v1.TodoListService.DeleteTodoList:output_type -> google.protobuf.Empty
+	29, // 48: todolist.v1.TodoListService.ArchiveTodoList:output_type -> google.protobuf.Empty
+	29, // 49: todolist.v1.TodoListService.UnarchiveTodoList:output_type -> google.protobuf.Empty
+	1,  // 50: todolist.v1.TodoListService.CloneTodoList:output_type -> todolist.v1.TodoList
+	1,  // 51: todolist.v1.TodoListService.InstantiateTemplate:output_type -> todolist.v1.TodoList
+	3,  // 52: todolist.v1.TodoListService.WatchTodoList:output_type -> todolist.v1.Event
+	0,  // 53: todolist.v1.TodoListService.AddTodoItem:output_type -> todolist.v1.TodoItem
+	0,  // 54: todolist.v1.TodoListService.GetTodoItem:output_type -> todolist.v1.TodoItem
+	29, // 55: todolist.v1.TodoListService.UpdateTodoItem:output_type -> google.protobuf.Empty
+	29, // 56: todolist.v1.TodoListService.UpdateTodoSeries:output_type -> google.protobuf.Empty
+	29, // 57: todolist.v1.TodoListService.DeleteTodoItem:output_type -> google.protobuf.Empty
+	0,  // 58: todolist.v1.TodoListService.MoveTodoItem:output_type -> todolist.v1.TodoItem
+	0,  // 59: todolist.v1.TodoListService.AddTodoItemTags:output_type -> todolist.v1.TodoItem
+	0,  // 60: todolist.v1.TodoListService.RemoveTodoItemTags:output_type -> todolist.v1.TodoItem
+	25, // 61: todolist.v1.TodoListService.GetItemsByTags:output_type -> todolist.v1.TodoItems
+	25, // 62: todolist.v1.TodoListService.GetOverdueItems:output_type -> todolist.v1.TodoItems
+	25, // 63: todolist.v1.TodoListService.GetItemsDueBetween:output_type -> todolist.v1.TodoItems
+	43, // [43:64] is the sub-list for method output_type
+	22, // [22:43] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_todolist_proto_init() }
+func file_todolist_proto_init() {
+	if File_todolist_proto != nil {
+		return
+	}
+	file_todolist_proto_msgTypes[20].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_todolist_proto_rawDesc), len(file_todolist_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   27,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_todolist_proto_goTypes,
+		DependencyIndexes: file_todolist_proto_depIdxs,
+		MessageInfos:      file_todolist_proto_msgTypes,
+	}.Build()
+	File_todolist_proto = out.File
+	file_todolist_proto_goTypes = nil
+	file_todolist_proto_depIdxs = nil
+}
@@ -0,0 +1,943 @@
+// gRPC API of the todolist-management service, over the same core as its
+// JSON endpoints
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.3
+// source: todolist.proto
+
+package todolistpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TodoListService_AddTodoList_FullMethodName         = "/todolist.v1.TodoListService/AddTodoList"
+	TodoListService_GetTodoList_FullMethodName         = "/todolist.v1.TodoListService/GetTodoList"
+	TodoListService_ListTodoLists_FullMethodName       = "/todolist.v1.TodoListService/ListTodoLists"
+	TodoListService_EditTodoListName_FullMethodName    = "/todolist.v1.TodoListService/EditTodoListName"
+	TodoListService_DeleteTodoList_FullMethodName      = "/todolist.v1.TodoListService/DeleteTodoList"
+	TodoListService_ArchiveTodoList_FullMethodName     = "/todolist.v1.TodoListService/ArchiveTodoList"
+	TodoListService_UnarchiveTodoList_FullMethodName   = "/todolist.v1.TodoListService/UnarchiveTodoList"
+	TodoListService_CloneTodoList_FullMethodName       = "/todolist.v1.TodoListService/CloneTodoList"
+	TodoListService_InstantiateTemplate_FullMethodName = "/todolist.v1.TodoListService/InstantiateTemplate"
+	TodoListService_WatchTodoList_FullMethodName       = "/todolist.v1.TodoListService/WatchTodoList"
+	TodoListService_AddTodoItem_FullMethodName         = "/todolist.v1.TodoListService/AddTodoItem"
+	TodoListService_GetTodoItem_FullMethodName         = "/todolist.v1.TodoListService/GetTodoItem"
+	TodoListService_UpdateTodoItem_FullMethodName      = "/todolist.v1.TodoListService/UpdateTodoItem"
+	TodoListService_UpdateTodoSeries_FullMethodName    = "/todolist.v1.TodoListService/UpdateTodoSeries"
+	TodoListService_DeleteTodoItem_FullMethodName      = "/todolist.v1.TodoListService/DeleteTodoItem"
+	TodoListService_MoveTodoItem_FullMethodName        = "/todolist.v1.TodoListService/MoveTodoItem"
+	TodoListService_AddTodoItemTags_FullMethodName     = "/todolist.v1.TodoListService/AddTodoItemTags"
+	TodoListService_RemoveTodoItemTags_FullMethodName  = "/todolist.v1.TodoListService/RemoveTodoItemTags"
+	TodoListService_GetItemsByTags_FullMethodName      = "/todolist.v1.TodoListService/GetItemsByTags"
+	TodoListService_GetOverdueItems_FullMethodName     = "/todolist.v1.TodoListService/GetOverdueItems"
+	TodoListService_GetItemsDueBetween_FullMethodName  = "/todolist.v1.TodoListService/GetItemsDueBetween"
+)
+
+// TodoListServiceClient is the client API for TodoListService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TodoListService manages the todo lists and their items
+type TodoListServiceClient interface {
+	// AddTodoList creates a list with its items, and their children
+	AddTodoList(ctx context.Context, in *AddTodoListRequest, opts ...grpc.CallOption) (*TodoList, error)
+	// GetTodoList returns a list with its items, only the ones matching the
+	// filter when given
+	GetTodoList(ctx context.Context, in *GetTodoListRequest, opts ...grpc.CallOption) (*TodoList, error)
+	// ListTodoLists returns a page of the lists, without their items
+	ListTodoLists(ctx context.Context, in *ListTodoListsRequest, opts ...grpc.CallOption) (*ListTodoListsResponse, error)
+	// EditTodoListName renames a list
+	EditTodoListName(ctx context.Context, in *EditTodoListNameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// DeleteTodoList deletes a list with its items
+	DeleteTodoList(ctx context.Context, in *DeleteTodoListRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ArchiveTodoList archives a list, it can't be written to until unarchived
+	ArchiveTodoList(ctx context.Context, in *ArchiveTodoListRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// UnarchiveTodoList restores an archived list
+	UnarchiveTodoList(ctx context.Context, in *UnarchiveTodoListRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// CloneTodoList creates a copy of a list
+	CloneTodoList(ctx context.Context, in *CloneTodoListRequest, opts ...grpc.CallOption) (*TodoList, error)
+	// InstantiateTemplate creates a list from a template
+	InstantiateTemplate(ctx context.Context, in *InstantiateTemplateRequest, opts ...grpc.CallOption) (*TodoList, error)
+	// WatchTodoList streams the changes of a list, following last_event_id
+	// when given
+	WatchTodoList(ctx context.Context, in *WatchTodoListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+	// AddTodoItem adds an item to a list
+	AddTodoItem(ctx context.Context, in *AddTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error)
+	// GetTodoItem returns an item
+	GetTodoItem(ctx context.Context, in *GetTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error)
+	// UpdateTodoItem updates the value, completion, due date, priority and
+	// recurrence of an item, only the ones in the update mask when given
+	UpdateTodoItem(ctx context.Context, in *UpdateTodoItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// UpdateTodoSeries updates the incomplete occurrences of a recurring item
+	UpdateTodoSeries(ctx context.Context, in *UpdateTodoSeriesRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// DeleteTodoItem deletes an item, its children along with it when
+	// cascade is set or else moved up to its parent
+	DeleteTodoItem(ctx context.Context, in *DeleteTodoItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// MoveTodoItem moves an item within its list, or to another one
+	MoveTodoItem(ctx context.Context, in *MoveTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error)
+	// AddTodoItemTags tags an item
+	AddTodoItemTags(ctx context.Context, in *TodoItemTagsRequest, opts ...grpc.CallOption) (*TodoItem, error)
+	// RemoveTodoItemTags untags an item
+	RemoveTodoItemTags(ctx context.Context, in *TodoItemTagsRequest, opts ...grpc.CallOption) (*TodoItem, error)
+	// GetItemsByTags returns the items matching a tag filter
+	GetItemsByTags(ctx context.Context, in *GetItemsByTagsRequest, opts ...grpc.CallOption) (*TodoItems, error)
+	// GetOverdueItems returns the incomplete items past their due date
+	GetOverdueItems(ctx context.Context, in *GetOverdueItemsRequest, opts ...grpc.CallOption) (*TodoItems, error)
+	// GetItemsDueBetween returns the incomplete items due in [from, to)
+	GetItemsDueBetween(ctx context.Context, in *GetItemsDueBetweenRequest, opts ...grpc.CallOption) (*TodoItems, error)
+}
+
+type todoListServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTodoListServiceClient(cc grpc.ClientConnInterface) TodoListServiceClient {
+	return &todoListServiceClient{cc}
+}
+
+func (c *todoListServiceClient) AddTodoList(ctx context.Context, in *AddTodoListRequest, opts ...grpc.CallOption) (*TodoList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoList)
+	err := c.cc.Invoke(ctx, TodoListService_AddTodoList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) GetTodoList(ctx context.Context, in *GetTodoListRequest, opts ...grpc.CallOption) (*TodoList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoList)
+	err := c.cc.Invoke(ctx, TodoListService_GetTodoList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) ListTodoLists(ctx context.Context, in *ListTodoListsRequest, opts ...grpc.CallOption) (*ListTodoListsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTodoListsResponse)
+	err := c.cc.Invoke(ctx, TodoListService_ListTodoLists_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) EditTodoListName(ctx context.Context, in *EditTodoListNameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TodoListService_EditTodoListName_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) DeleteTodoList(ctx context.Context, in *DeleteTodoListRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TodoListService_DeleteTodoList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) ArchiveTodoList(ctx context.Context, in *ArchiveTodoListRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TodoListService_ArchiveTodoList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) UnarchiveTodoList(ctx context.Context, in *UnarchiveTodoListRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TodoListService_UnarchiveTodoList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) CloneTodoList(ctx context.Context, in *CloneTodoListRequest, opts ...grpc.CallOption) (*TodoList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoList)
+	err := c.cc.Invoke(ctx, TodoListService_CloneTodoList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) InstantiateTemplate(ctx context.Context, in *InstantiateTemplateRequest, opts ...grpc.CallOption) (*TodoList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoList)
+	err := c.cc.Invoke(ctx, TodoListService_InstantiateTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) WatchTodoList(ctx context.Context, in *WatchTodoListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TodoListService_ServiceDesc.Streams[0], TodoListService_WatchTodoList_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTodoListRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TodoListService_WatchTodoListClient = grpc.ServerStreamingClient[Event]
+
+func (c *todoListServiceClient) AddTodoItem(ctx context.Context, in *AddTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoItem)
+	err := c.cc.Invoke(ctx, TodoListService_AddTodoItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) GetTodoItem(ctx context.Context, in *GetTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoItem)
+	err := c.cc.Invoke(ctx, TodoListService_GetTodoItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) UpdateTodoItem(ctx context.Context, in *UpdateTodoItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TodoListService_UpdateTodoItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) UpdateTodoSeries(ctx context.Context, in *UpdateTodoSeriesRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TodoListService_UpdateTodoSeries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) DeleteTodoItem(ctx context.Context, in *DeleteTodoItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TodoListService_DeleteTodoItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) MoveTodoItem(ctx context.Context, in *MoveTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoItem)
+	err := c.cc.Invoke(ctx, TodoListService_MoveTodoItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) AddTodoItemTags(ctx context.Context, in *TodoItemTagsRequest, opts ...grpc.CallOption) (*TodoItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoItem)
+	err := c.cc.Invoke(ctx, TodoListService_AddTodoItemTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) RemoveTodoItemTags(ctx context.Context, in *TodoItemTagsRequest, opts ...grpc.CallOption) (*TodoItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoItem)
+	err := c.cc.Invoke(ctx, TodoListService_RemoveTodoItemTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) GetItemsByTags(ctx context.Context, in *GetItemsByTagsRequest, opts ...grpc.CallOption) (*TodoItems, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoItems)
+	err := c.cc.Invoke(ctx, TodoListService_GetItemsByTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) GetOverdueItems(ctx context.Context, in *GetOverdueItemsRequest, opts ...grpc.CallOption) (*TodoItems, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoItems)
+	err := c.cc.Invoke(ctx, TodoListService_GetOverdueItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoListServiceClient) GetItemsDueBetween(ctx context.Context, in *GetItemsDueBetweenRequest, opts ...grpc.CallOption) (*TodoItems, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoItems)
+	err := c.cc.Invoke(ctx, TodoListService_GetItemsDueBetween_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TodoListServiceServer is the server API for TodoListService service.
+// All implementations must embed UnimplementedTodoListServiceServer
+// for forward compatibility.
+//
+// TodoListService manages the todo lists and their items
+type TodoListServiceServer interface {
+	// AddTodoList creates a list with its items, and their children
+	AddTodoList(context.Context, *AddTodoListRequest) (*TodoList, error)
+	// GetTodoList returns a list with its items, only the ones matching the
+	// filter when given
+	GetTodoList(context.Context, *GetTodoListRequest) (*TodoList, error)
+	// ListTodoLists returns a page of the lists, without their items
+	ListTodoLists(context.Context, *ListTodoListsRequest) (*ListTodoListsResponse, error)
+	// EditTodoListName renames a list
+	EditTodoListName(context.Context, *EditTodoListNameRequest) (*emptypb.Empty, error)
+	// DeleteTodoList deletes a list with its items
+	DeleteTodoList(context.Context, *DeleteTodoListRequest) (*emptypb.Empty, error)
+	// ArchiveTodoList archives a list, it can't be written to until unarchived
+	ArchiveTodoList(context.Context, *ArchiveTodoListRequest) (*emptypb.Empty, error)
+	// UnarchiveTodoList restores an archived list
+	UnarchiveTodoList(context.Context, *UnarchiveTodoListRequest) (*emptypb.Empty, error)
+	// CloneTodoList creates a copy of a list
+	CloneTodoList(context.Context, *CloneTodoListRequest) (*TodoList, error)
+	// InstantiateTemplate creates a list from a template
+	InstantiateTemplate(context.Context, *InstantiateTemplateRequest) (*TodoList, error)
+	// WatchTodoList streams the changes of a list, following last_event_id
+	// when given
+	WatchTodoList(*WatchTodoListRequest, grpc.ServerStreamingServer[Event]) error
+	// AddTodoItem adds an item to a list
+	AddTodoItem(context.Context, *AddTodoItemRequest) (*TodoItem, error)
+	// GetTodoItem returns an item
+	GetTodoItem(context.Context, *GetTodoItemRequest) (*TodoItem, error)
+	// UpdateTodoItem updates the value, completion, due date, priority and
+	// recurrence of an item, only the ones in the update mask when given
+	UpdateTodoItem(context.Context, *UpdateTodoItemRequest) (*emptypb.Empty, error)
+	// UpdateTodoSeries updates the incomplete occurrences of a recurring item
+	UpdateTodoSeries(context.Context, *UpdateTodoSeriesRequest) (*emptypb.Empty, error)
+	// DeleteTodoItem deletes an item, its children along with it when
+	// cascade is set or else moved up to its parent
+	DeleteTodoItem(context.Context, *DeleteTodoItemRequest) (*emptypb.Empty, error)
+	// MoveTodoItem moves an item within its list, or to another one
+	MoveTodoItem(context.Context, *MoveTodoItemRequest) (*TodoItem, error)
+	// AddTodoItemTags tags an item
+	AddTodoItemTags(context.Context, *TodoItemTagsRequest) (*TodoItem, error)
+	// RemoveTodoItemTags untags an item
+	RemoveTodoItemTags(context.Context, *TodoItemTagsRequest) (*TodoItem, error)
+	// GetItemsByTags returns the items matching a tag filter
+	GetItemsByTags(context.Context, *GetItemsByTagsRequest) (*TodoItems, error)
+	// GetOverdueItems returns the incomplete items past their due date
+	GetOverdueItems(context.Context, *GetOverdueItemsRequest) (*TodoItems, error)
+	// GetItemsDueBetween returns the incomplete items due in [from, to)
+	GetItemsDueBetween(context.Context, *GetItemsDueBetweenRequest) (*TodoItems, error)
+	mustEmbedUnimplementedTodoListServiceServer()
+}
+
+// UnimplementedTodoListServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTodoListServiceServer struct{}
+
+func (UnimplementedTodoListServiceServer) AddTodoList(context.Context, *AddTodoListRequest) (*TodoList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTodoList not implemented")
+}
+func (UnimplementedTodoListServiceServer) GetTodoList(context.Context, *GetTodoListRequest) (*TodoList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTodoList not implemented")
+}
+func (UnimplementedTodoListServiceServer) ListTodoLists(context.Context, *ListTodoListsRequest) (*ListTodoListsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTodoLists not implemented")
+}
+func (UnimplementedTodoListServiceServer) EditTodoListName(context.Context, *EditTodoListNameRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditTodoListName not implemented")
+}
+func (UnimplementedTodoListServiceServer) DeleteTodoList(context.Context, *DeleteTodoListRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTodoList not implemented")
+}
+func (UnimplementedTodoListServiceServer) ArchiveTodoList(context.Context, *ArchiveTodoListRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveTodoList not implemented")
+}
+func (UnimplementedTodoListServiceServer) UnarchiveTodoList(context.Context, *UnarchiveTodoListRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnarchiveTodoList not implemented")
+}
+func (UnimplementedTodoListServiceServer) CloneTodoList(context.Context, *CloneTodoListRequest) (*TodoList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneTodoList not implemented")
+}
+func (UnimplementedTodoListServiceServer) InstantiateTemplate(context.Context, *InstantiateTemplateRequest) (*TodoList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InstantiateTemplate not implemented")
+}
+func (UnimplementedTodoListServiceServer) WatchTodoList(*WatchTodoListRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTodoList not implemented")
+}
+func (UnimplementedTodoListServiceServer) AddTodoItem(context.Context, *AddTodoItemRequest) (*TodoItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTodoItem not implemented")
+}
+func (UnimplementedTodoListServiceServer) GetTodoItem(context.Context, *GetTodoItemRequest) (*TodoItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTodoItem not implemented")
+}
+func (UnimplementedTodoListServiceServer) UpdateTodoItem(context.Context, *UpdateTodoItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTodoItem not implemented")
+}
+func (UnimplementedTodoListServiceServer) UpdateTodoSeries(context.Context, *UpdateTodoSeriesRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTodoSeries not implemented")
+}
+func (UnimplementedTodoListServiceServer) DeleteTodoItem(context.Context, *DeleteTodoItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTodoItem not implemented")
+}
+func (UnimplementedTodoListServiceServer) MoveTodoItem(context.Context, *MoveTodoItemRequest) (*TodoItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveTodoItem not implemented")
+}
+func (UnimplementedTodoListServiceServer) AddTodoItemTags(context.Context, *TodoItemTagsRequest) (*TodoItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTodoItemTags not implemented")
+}
+func (UnimplementedTodoListServiceServer) RemoveTodoItemTags(context.Context, *TodoItemTagsRequest) (*TodoItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveTodoItemTags not implemented")
+}
+func (UnimplementedTodoListServiceServer) GetItemsByTags(context.Context, *GetItemsByTagsRequest) (*TodoItems, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetItemsByTags not implemented")
+}
+func (UnimplementedTodoListServiceServer) GetOverdueItems(context.Context, *GetOverdueItemsRequest) (*TodoItems, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOverdueItems not implemented")
+}
+func (UnimplementedTodoListServiceServer) GetItemsDueBetween(context.Context, *GetItemsDueBetweenRequest) (*TodoItems, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetItemsDueBetween not implemented")
+}
+func (UnimplementedTodoListServiceServer) mustEmbedUnimplementedTodoListServiceServer() {}
+func (UnimplementedTodoListServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeTodoListServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TodoListServiceServer will
+// result in compilation errors.
+type UnsafeTodoListServiceServer interface {
+	mustEmbedUnimplementedTodoListServiceServer()
+}
+
+func RegisterTodoListServiceServer(s grpc.ServiceRegistrar, srv TodoListServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTodoListServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TodoListService_ServiceDesc, srv)
+}
+
+func _TodoListService_AddTodoList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTodoListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).AddTodoList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_AddTodoList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).AddTodoList(ctx, req.(*AddTodoListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_GetTodoList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTodoListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).GetTodoList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_GetTodoList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).GetTodoList(ctx, req.(*GetTodoListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_ListTodoLists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodoListsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).ListTodoLists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_ListTodoLists_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).ListTodoLists(ctx, req.(*ListTodoListsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_EditTodoListName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditTodoListNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).EditTodoListName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_EditTodoListName_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).EditTodoListName(ctx, req.(*EditTodoListNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_DeleteTodoList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTodoListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).DeleteTodoList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_DeleteTodoList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).DeleteTodoList(ctx, req.(*DeleteTodoListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_ArchiveTodoList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveTodoListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).ArchiveTodoList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_ArchiveTodoList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).ArchiveTodoList(ctx, req.(*ArchiveTodoListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_UnarchiveTodoList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnarchiveTodoListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).UnarchiveTodoList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_UnarchiveTodoList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).UnarchiveTodoList(ctx, req.(*UnarchiveTodoListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_CloneTodoList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneTodoListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).CloneTodoList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_CloneTodoList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).CloneTodoList(ctx, req.(*CloneTodoListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_InstantiateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstantiateTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).InstantiateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_InstantiateTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).InstantiateTemplate(ctx, req.(*InstantiateTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_WatchTodoList_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTodoListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TodoListServiceServer).WatchTodoList(m, &grpc.GenericServerStream[WatchTodoListRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TodoListService_WatchTodoListServer = grpc.ServerStreamingServer[Event]
+
+func _TodoListService_AddTodoItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTodoItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).AddTodoItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_AddTodoItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).AddTodoItem(ctx, req.(*AddTodoItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_GetTodoItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTodoItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).GetTodoItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_GetTodoItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).GetTodoItem(ctx, req.(*GetTodoItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_UpdateTodoItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTodoItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).UpdateTodoItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_UpdateTodoItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).UpdateTodoItem(ctx, req.(*UpdateTodoItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_UpdateTodoSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTodoSeriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).UpdateTodoSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_UpdateTodoSeries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).UpdateTodoSeries(ctx, req.(*UpdateTodoSeriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_DeleteTodoItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTodoItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).DeleteTodoItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_DeleteTodoItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).DeleteTodoItem(ctx, req.(*DeleteTodoItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_MoveTodoItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveTodoItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).MoveTodoItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_MoveTodoItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).MoveTodoItem(ctx, req.(*MoveTodoItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_AddTodoItemTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TodoItemTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).AddTodoItemTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_AddTodoItemTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).AddTodoItemTags(ctx, req.(*TodoItemTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_RemoveTodoItemTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TodoItemTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).RemoveTodoItemTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_RemoveTodoItemTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).RemoveTodoItemTags(ctx, req.(*TodoItemTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_GetItemsByTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemsByTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).GetItemsByTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_GetItemsByTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).GetItemsByTags(ctx, req.(*GetItemsByTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_GetOverdueItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOverdueItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).GetOverdueItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_GetOverdueItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).GetOverdueItems(ctx, req.(*GetOverdueItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoListService_GetItemsDueBetween_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemsDueBetweenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoListServiceServer).GetItemsDueBetween(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoListService_GetItemsDueBetween_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoListServiceServer).GetItemsDueBetween(ctx, req.(*GetItemsDueBetweenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TodoListService_ServiceDesc is the grpc.ServiceDesc for TodoListService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TodoListService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "todolist.v1.TodoListService",
+	HandlerType: (*TodoListServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddTodoList",
+			Handler:    _TodoListService_AddTodoList_Handler,
+		},
+		{
+			MethodName: "GetTodoList",
+			Handler:    _TodoListService_GetTodoList_Handler,
+		},
+		{
+			MethodName: "ListTodoLists",
+			Handler:    _TodoListService_ListTodoLists_Handler,
+		},
+		{
+			MethodName: "EditTodoListName",
+			Handler:    _TodoListService_EditTodoListName_Handler,
+		},
+		{
+			MethodName: "DeleteTodoList",
+			Handler:    _TodoListService_DeleteTodoList_Handler,
+		},
+		{
+			MethodName: "ArchiveTodoList",
+			Handler:    _TodoListService_ArchiveTodoList_Handler,
+		},
+		{
+			MethodName: "UnarchiveTodoList",
+			Handler:    _TodoListService_UnarchiveTodoList_Handler,
+		},
+		{
+			MethodName: "CloneTodoList",
+			Handler:    _TodoListService_CloneTodoList_Handler,
+		},
+		{
+			MethodName: "InstantiateTemplate",
+			Handler:    _TodoListService_InstantiateTemplate_Handler,
+		},
+		{
+			MethodName: "AddTodoItem",
+			Handler:    _TodoListService_AddTodoItem_Handler,
+		},
+		{
+			MethodName: "GetTodoItem",
+			Handler:    _TodoListService_GetTodoItem_Handler,
+		},
+		{
+			MethodName: "UpdateTodoItem",
+			Handler:    _TodoListService_UpdateTodoItem_Handler,
+		},
+		{
+			MethodName: "UpdateTodoSeries",
+			Handler:    _TodoListService_UpdateTodoSeries_Handler,
+		},
+		{
+			MethodName: "DeleteTodoItem",
+			Handler:    _TodoListService_DeleteTodoItem_Handler,
+		},
+		{
+			MethodName: "MoveTodoItem",
+			Handler:    _TodoListService_MoveTodoItem_Handler,
+		},
+		{
+			MethodName: "AddTodoItemTags",
+			Handler:    _TodoListService_AddTodoItemTags_Handler,
+		},
+		{
+			MethodName: "RemoveTodoItemTags",
+			Handler:    _TodoListService_RemoveTodoItemTags_Handler,
+		},
+		{
+			MethodName: "GetItemsByTags",
+			Handler:    _TodoListService_GetItemsByTags_Handler,
+		},
+		{
+			MethodName: "GetOverdueItems",
+			Handler:    _TodoListService_GetOverdueItems_Handler,
+		},
+		{
+			MethodName: "GetItemsDueBetween",
+			Handler:    _TodoListService_GetItemsDueBetween_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchTodoList",
+			Handler:       _TodoListService_WatchTodoList_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "todolist.proto",
+}
@@ -0,0 +1,243 @@
+// Package ratelimit limits the rate of the requests of every client, by
+// token buckets kept in a Store
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/auth"
+	"github.com/Shivam010/go-rest-api/httperr"
+)
+
+// Limit is the rate of a token bucket, refilled by Rate tokens a second up
+// to Burst tokens
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute returns the limit of n requests a minute, all of which may be
+// made at once
+func PerMinute(n int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: n}
+}
+
+// window returns the time the bucket takes to refill from empty, in seconds
+func (l Limit) window() int {
+	return int(math.Ceil(float64(l.Burst) / l.Rate))
+}
+
+// Result of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Store keeps the token buckets, the in-memory one is enough for a single
+// instance of a service, instances sharing their limits need a shared one
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+// refill adds the tokens earned since the last take
+func (b *bucket) refill(now time.Time) {
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate)
+	b.last = now
+}
+
+// MemoryStore keeps the buckets in memory, forgetting them once full
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore returns an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}}
+}
+
+// Take takes a token from the bucket of key
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok || b.limit != limit {
+		b = &bucket{tokens: float64(limit.Burst), last: now, limit: limit}
+		s.buckets[key] = b
+	}
+	b.refill(now)
+
+	res := Result{}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / limit.Rate)
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = seconds((float64(limit.Burst) - b.tokens) / limit.Rate)
+	return res, nil
+}
+
+// sweep forgets, once a minute, the buckets which are full again
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if b.refill(now); b.tokens >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// Limiter limits the requests of every client to each route, a client being
+// the user it authenticated as or else its IP
+type Limiter struct {
+	store   Store
+	trusted []*net.IPNet
+	Default Limit
+	Routes  map[string]Limit
+	// PerIP limits all the requests of an IP, authenticated or not
+	PerIP Limit
+}
+
+// NewLimiter returns a limiter of 60 requests a minute to every route, and
+// of 300 a minute of every IP, the X-Forwarded-For header being trusted when
+// sent by one of the trusted proxies, given as CIDRs or IPs
+func NewLimiter(store Store, trustedProxies []string) (*Limiter, error) {
+	l := &Limiter{store: store, Default: PerMinute(60), Routes: map[string]Limit{}, PerIP: PerMinute(300)}
+	for _, proxy := range trustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, err
+		}
+		l.trusted = append(l.trusted, network)
+	}
+	return l, nil
+}
+
+func (l *Limiter) isTrusted(ip net.IP) bool {
+	for _, network := range l.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the IP of the client of a request, the last address of
+// its X-Forwarded-For header not of a trusted proxy when sent by one
+func (l *Limiter) ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !l.isTrusted(ip) {
+		return host
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		hopIP := net.ParseIP(hop)
+		if hopIP == nil {
+			break
+		}
+		if host = hop; !l.isTrusted(hopIP) {
+			break
+		}
+	}
+	return host
+}
+
+// principal returns the client of a request, the principal it authenticated
+// as, else its IP
+func (l *Limiter) principal(r *http.Request) string {
+	if user, ok := auth.Principal(r.Context()); ok {
+		return "user:" + user
+	}
+	return "ip:" + l.ClientIP(r)
+}
+
+// Handler limits the requests of next by the limit of the route they
+// matched, telling the client of its quota in the RateLimit headers and
+// answering with a 429 once exceeded. The handler is to run after the
+// authentication, which sets the principal of the request
+func (l *Limiter) Handler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, ok := l.Routes[r.Pattern]
+		if !ok {
+			limit = l.Default
+		}
+		l.serve(w, r, next, r.Pattern+" "+l.principal(r), limit)
+	}
+}
+
+// IPHandler limits the requests of next by the IP of their client, whatever
+// their route, like Handler does. The handler is to run before the
+// authentication, so that the requests failing it are counted too
+func (l *Limiter) IPHandler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l.serve(w, r, next, "ip "+l.ClientIP(r), l.PerIP)
+	}
+}
+
+// serve takes a token from the bucket of key for a request, serving it
+// with next when allowed
+func (l *Limiter) serve(w http.ResponseWriter, r *http.Request, next http.HandlerFunc, key string, limit Limit) {
+	res, err := l.store.Take(r.Context(), key, limit, time.Now())
+	if err != nil {
+		// a store which is down lets the requests through
+		slog.Error("rate limit store error", "err", err)
+		next(w, r)
+		return
+	}
+
+	h := w.Header()
+	h.Set("RateLimit-Policy", strconv.Itoa(limit.Burst)+";w="+strconv.Itoa(limit.window()))
+	h.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+	h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(int(math.Ceil(res.Reset.Seconds()))))
+	if !res.Allowed {
+		h.Set("Retry-After", strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds()))))
+		httperr.Error(w, "429 Too Many Requests", http.StatusTooManyRequests)
+		return
+	}
+	next(w, r)
+}
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Shivam010/go-rest-api/auth"
+)
+
+func TestHandlerKeysOnPrincipal(t *testing.T) {
+	l, err := NewLimiter(NewMemoryStore(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Default = PerMinute(1)
+	h := l.Handler(func(w http.ResponseWriter, r *http.Request) {})
+
+	send := func(remote, user, principal string) int {
+		r := httptest.NewRequest("GET", "/lists", nil)
+		r.RemoteAddr = remote + ":1234"
+		if user != "" {
+			r.SetBasicAuth(user, "not verified")
+		}
+		if principal != "" {
+			r = r.WithContext(auth.WithPrincipal(r.Context(), principal))
+		}
+		w := httptest.NewRecorder()
+		h(w, r)
+		return w.Code
+	}
+
+	if code := send("10.0.0.1", "", ""); code != http.StatusOK {
+		t.Fatalf("first request = %d, want 200", code)
+	}
+	// an unverified username doesn't give a client a quota of its own
+	if code := send("10.0.0.1", "someone", ""); code != http.StatusTooManyRequests {
+		t.Errorf("request with an unverified user = %d, want 429", code)
+	}
+	// the principal set by the authentication does, wherever it comes from
+	if code := send("10.0.0.1", "mavis", "mavis"); code != http.StatusOK {
+		t.Errorf("authenticated request = %d, want 200", code)
+	}
+	if code := send("10.0.0.2", "mavis", "mavis"); code != http.StatusTooManyRequests {
+		t.Errorf("authenticated request from another IP = %d, want 429", code)
+	}
+	if code := send("10.0.0.2", "", ""); code != http.StatusOK {
+		t.Errorf("anonymous request from another IP = %d, want 200", code)
+	}
+}
+
+func TestIPHandlerCountsRefusedRequests(t *testing.T) {
+	l, err := NewLimiter(NewMemoryStore(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.PerIP = PerMinute(2)
+	// the authentication refuses every request, after the IP limit
+	h := l.IPHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	send := func(remote, path string) int {
+		r := httptest.NewRequest("GET", path, nil)
+		r.RemoteAddr = remote + ":1234"
+		r.SetBasicAuth("mavis", "guess")
+		w := httptest.NewRecorder()
+		h(w, r)
+		return w.Code
+	}
+
+	for _, path := range []string{"/lists", "/items"} {
+		if code := send("10.0.0.1", path); code != http.StatusUnauthorized {
+			t.Fatalf("GET %s = %d, want 401", path, code)
+		}
+	}
+	// the quota of an IP is shared by the routes
+	if code := send("10.0.0.1", "/webhooks"); code != http.StatusTooManyRequests {
+		t.Errorf("third refused request = %d, want 429", code)
+	}
+	if code := send("10.0.0.2", "/webhooks"); code != http.StatusUnauthorized {
+		t.Errorf("request from another IP = %d, want 401", code)
+	}
+}
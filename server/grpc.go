@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/Shivam010/go-rest-api/auth"
+)
+
+// NewGRPC returns a gRPC server of the services registered by register,
+// along with the health and reflection services. The calls of the other
+// services must carry Basic Auth credentials accepted by authorized in their
+// authorization metadata, a unary call is bounded by RequestTimeout and a
+// streaming one ends once the servers start shutting down. The server serves
+// over TLS when TLS_CERT_FILE and TLS_KEY_FILE are set
+func NewGRPC(register func(*grpc.Server), authorized func(user, pass string) bool) (*grpc.Server, error) {
+	i := interceptors{authorized}
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(i.unary),
+		grpc.StreamInterceptor(i.stream),
+	}
+	if certFile, keyFile := os.Getenv("TLS_CERT_FILE"), os.Getenv("TLS_KEY_FILE"); certFile != "" && keyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	gs := grpc.NewServer(opts...)
+	register(gs)
+
+	hs := grpchealth.NewServer()
+	for name := range gs.GetServiceInfo() {
+		hs.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(gs, hs)
+	reflection.Register(gs)
+	return gs, nil
+}
+
+// interceptors of the calls of a gRPC server
+type interceptors struct {
+	authorized func(user, pass string) bool
+}
+
+// unary authenticates a unary call and bounds it by RequestTimeout
+func (i interceptors) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := i.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, RequestTimeout)
+	defer cancel()
+	return handler(ctx, req)
+}
+
+// stream authenticates a streaming call and ends it once the servers start
+// shutting down
+func (i interceptors) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := i.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := StreamContext(ctx)
+	defer cancel()
+	return handler(srv, &serverStream{ss, ctx})
+}
+
+// serverStream is a stream of a context of its own
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate checks the Basic Auth credentials of a call, parsed as the
+// ones of a HTTP request, but for the calls of the health and reflection
+// services, and returns the context of the call carrying its principal
+func (i interceptors) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if strings.HasPrefix(method, "/grpc.health.v1.") || strings.HasPrefix(method, "/grpc.reflection.") {
+		return ctx, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		r := &http.Request{Header: http.Header{"Authorization": {v}}}
+		if user, pass, ok := r.BasicAuth(); ok && i.authorized(user, pass) {
+			return auth.WithPrincipal(ctx, user), nil
+		}
+	}
+	return ctx, status.Error(codes.Unauthenticated, "Unauthorized Access")
+}
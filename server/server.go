@@ -0,0 +1,176 @@
+// Package server runs the api, gRPC and admin servers of the services and
+// shuts them down gracefully
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// RequestTimeout bounds the time a request, and the SQL it runs, may take,
+// and ShutdownTimeout the time the servers, and then the services, take to
+// shut down
+const (
+	RequestTimeout  = 10 * time.Second
+	ShutdownTimeout = 30 * time.Second
+)
+
+// server limits, the write timeout leaves a request the time to time out
+// on its own first
+const (
+	readHeaderTimeout = 5 * time.Second
+	readTimeout       = 15 * time.Second
+	writeTimeout      = RequestTimeout + 5*time.Second
+	idleTimeout       = 2 * time.Minute
+	maxHeaderBytes    = 64 << 10
+)
+
+// shuttingDown is closed once the servers start shutting down, ending the
+// streams which would otherwise hold the shutdown until its timeout
+var (
+	shuttingDown = make(chan struct{})
+	shutdownOnce sync.Once
+)
+
+func stopStreams() {
+	shutdownOnce.Do(func() { close(shuttingDown) })
+}
+
+// StreamContext returns the context of a long-lived response, like a stream
+// of events, which is done along with ctx or once the servers start shutting
+// down; the client is expected to reconnect to another instance
+func StreamContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-shuttingDown:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// getenv returns the value of an environment variable, fallback if unset
+func getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// New returns a http server of h with the server limits set, its streams
+// being stopped when it is shut down
+func New(addr string, h http.Handler) *http.Server {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           h,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+		MaxHeaderBytes:    maxHeaderBytes,
+		TLSConfig:         &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+	srv.RegisterOnShutdown(stopStreams)
+	return srv
+}
+
+// AdminMux returns the handler of the admin server
+func AdminMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// Serve runs the api server, the gRPC one if given and the admin one if
+// asked for, until SIGINT or SIGTERM or until one of them fails, then drains
+// their in-flight requests. The servers are set up from the environment:
+//   - ADDR, the address of the api server, ":8080" by default
+//   - TLS_CERT_FILE and TLS_KEY_FILE, to serve the api over TLS
+//   - GRPC_ADDR, the address of the gRPC server, ":9090" by default
+//   - ADMIN_ADDR, to start the admin server, on plain HTTP
+func Serve(api, admin http.Handler, gs *grpc.Server) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	certFile, keyFile := os.Getenv("TLS_CERT_FILE"), os.Getenv("TLS_KEY_FILE")
+	servers := []*http.Server{New(getenv("ADDR", ":8080"), api)}
+	if addr := os.Getenv("ADMIN_ADDR"); addr != "" {
+		servers = append(servers, New(addr, admin))
+	}
+
+	errc := make(chan error, len(servers)+1)
+	if gs != nil {
+		addr := getenv("GRPC_ADDR", ":9090")
+		lis, err := net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+		go func() {
+			slog.Info("listening", "addr", addr, "grpc", true)
+			if err := gs.Serve(lis); err != nil {
+				errc <- err
+			}
+		}()
+	}
+	for i, srv := range servers {
+		go func(srv *http.Server, tls bool) {
+			slog.Info("listening", "addr", srv.Addr, "tls", tls)
+			var err error
+			if tls {
+				err = srv.ListenAndServeTLS(certFile, keyFile)
+			} else {
+				err = srv.ListenAndServe()
+			}
+			if err != http.ErrServerClosed {
+				errc <- err
+			}
+		}(srv, i == 0 && certFile != "" && keyFile != "")
+	}
+
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		slog.Info("shutting down")
+	}
+
+	// the streams end first, for the shutdown to only wait for the requests
+	stopStreams()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	for _, srv := range servers {
+		if serr := srv.Shutdown(shutdownCtx); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	if gs != nil {
+		stopped := make(chan struct{})
+		go func() {
+			gs.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			gs.Stop()
+		}
+	}
+	return err
+}
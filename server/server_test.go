@@ -0,0 +1,48 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestShutdownEndsStreams(t *testing.T) {
+	ended := make(chan struct{})
+	srv := New("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(ended)
+		ctx, cancel := StreamContext(r.Context())
+		defer cancel()
+		io.WriteString(w, "started\n")
+		w.(http.Flusher).Flush()
+		<-ctx.Done()
+	}))
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(lis)
+
+	resp, err := http.Get("http://" + lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if line, err := bufio.NewReader(resp.Body).ReadString('\n'); err != nil || line != "started\n" {
+		t.Fatalf("stream = %q, %v", line, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() = %v, want the stream to end", err)
+	}
+	select {
+	case <-ended:
+	default:
+		t.Error("the stream is still running")
+	}
+}
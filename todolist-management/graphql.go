@@ -0,0 +1,464 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+
+	"github.com/Shivam010/go-rest-api/httperr"
+	"github.com/Shivam010/go-rest-api/logging"
+	"github.com/Shivam010/go-rest-api/todolist-management/lib"
+	"github.com/Shivam010/go-rest-api/tracing"
+	"github.com/Shivam010/go-rest-api/user-management/lib"
+)
+
+// GraphQL serves the GraphQL API of the lists, their items and the users,
+// the lists and users of a query being read in batches by its loaders
+type GraphQL struct {
+	c      *todolist.Core
+	s      *users.Store
+	schema graphql.Schema
+}
+
+// NewGraphQL ...
+func NewGraphQL(c *todolist.Core, s *users.Store) (*GraphQL, error) {
+	g := &GraphQL{c: c, s: s}
+	schema, err := g.newSchema()
+	if err != nil {
+		return nil, err
+	}
+	g.schema = schema
+	return g, nil
+}
+
+// graphqlRequest is a GraphQL query, POSTed as JSON or given by the query
+// parameters of a GET, its variables then being JSON encoded
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Query runs the query of a request and answers with its result; a query
+// costing more than graphqlMaxComplexity is not run, nor is a mutation sent
+// with a GET
+func (g *GraphQL) Query(w http.ResponseWriter, r *http.Request) {
+	req := &graphqlRequest{}
+	switch r.Method {
+	case "GET":
+		q := r.URL.Query()
+		req.Query, req.OperationName = q.Get("query"), q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				httperr.Error(w, "invalid variables", http.StatusBadRequest)
+				return
+			}
+		}
+	case "POST":
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			httperr.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	default:
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+
+	// a query which doesn't parse is left to graphql.Do to report
+	if doc, err := parser.Parse(parser.ParseParams{Source: req.Query}); err == nil {
+		if op := operation(doc, req.OperationName); op != nil {
+			if r.Method == "GET" && op.Operation == ast.OperationTypeMutation {
+				w.Header().Set("Allow", "POST")
+				httperr.Error(w, "mutations must be POSTed", http.StatusMethodNotAllowed)
+				return
+			}
+			if cost := complexity(doc, op, req.Variables, graphqlMaxComplexity); cost > graphqlMaxComplexity {
+				msg := fmt.Sprintf("query complexity exceeds the limit of %d", graphqlMaxComplexity)
+				ReturnJSONEncoded(w, r, &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(msg)}})
+				return
+			}
+		}
+	}
+
+	ctx := context.WithValue(r.Context(), loadersKey{}, g.newLoaders())
+	res := graphql.Do(graphql.Params{
+		Schema:         g.schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        ctx,
+	})
+	ReturnJSONEncoded(w, r, res)
+}
+
+// operation returns the operation of doc named name, or its only one without
+// a name; nil when there is no such operation
+func operation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op
+		}
+	}
+	return found
+}
+
+// connectionFields are the fields of the schema returning a connection, the
+// selections of which are made once for every node of a page
+var connectionFields = map[string]bool{"users": true, "lists": true, "items": true}
+
+// complexity returns the cost of an operation of doc, up to just over limit:
+// a field costs one, plus the cost of its selections times the page size of
+// a connection
+func complexity(doc *ast.Document, op *ast.OperationDefinition, vars map[string]interface{}, limit int) int {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[f.Name.Value] = f
+		}
+	}
+
+	// the cost of a fragment is computed once, a fragment spreading itself
+	// being left to the validation of the query
+	costs := map[string]int{}
+	visiting := map[string]bool{}
+	var cost func(set *ast.SelectionSet) int
+	cost = func(set *ast.SelectionSet) int {
+		if set == nil {
+			return 0
+		}
+		n := 0
+		for _, sel := range set.Selections {
+			switch s := sel.(type) {
+			case *ast.Field:
+				n += 1 + pageSize(s, vars)*cost(s.SelectionSet)
+			case *ast.InlineFragment:
+				n += cost(s.SelectionSet)
+			case *ast.FragmentSpread:
+				name := s.Name.Value
+				f, ok := fragments[name]
+				if !ok || visiting[name] {
+					continue
+				}
+				c, ok := costs[name]
+				if !ok {
+					visiting[name] = true
+					c = cost(f.SelectionSet)
+					delete(visiting, name)
+					costs[name] = c
+				}
+				n += c
+			}
+			if n > limit {
+				return limit + 1
+			}
+		}
+		return n
+	}
+	return cost(op.SelectionSet)
+}
+
+// pageSize returns the number of nodes a field selects, the first argument of
+// a connection and one for any other field
+func pageSize(f *ast.Field, vars map[string]interface{}) int {
+	if !connectionFields[f.Name.Value] {
+		return 1
+	}
+	for _, arg := range f.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+		var v interface{}
+		switch value := arg.Value.(type) {
+		case *ast.IntValue:
+			v, _ = strconv.Atoi(value.Value)
+		case *ast.Variable:
+			v = vars[value.Name.Value]
+		}
+		n := graphqlPageSize
+		switch v := v.(type) {
+		case int:
+			n = v
+		case float64:
+			n = int(v)
+		}
+		if n < 0 || n > graphqlMaxPageSize {
+			// rejected by the resolver
+			return 1
+		}
+		return n
+	}
+	return graphqlPageSize
+}
+
+// pageArgs returns the number of nodes and the offset of a page of a
+// connection, from its first and after arguments
+func pageArgs(args map[string]interface{}) (first, offset int, err error) {
+	first = graphqlPageSize
+	if v, ok := args["first"].(int); ok {
+		first = v
+	}
+	if first < 0 || first > graphqlMaxPageSize {
+		return 0, 0, badInput(fmt.Sprintf("first must be between 0 and %d", graphqlMaxPageSize))
+	}
+	if after, ok := args["after"].(string); ok && after != "" {
+		b, err := base64.RawURLEncoding.DecodeString(after)
+		if err != nil || !strings.HasPrefix(string(b), cursorPrefix) {
+			return 0, 0, badInput("invalid cursor")
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(string(b), cursorPrefix))
+		if err != nil || n < 0 {
+			return 0, 0, badInput("invalid cursor")
+		}
+		offset = n + 1
+	}
+	return first, offset, nil
+}
+
+// cursorPrefix prefixes the offsets of the nodes of a connection, in their
+// opaque cursor
+const cursorPrefix = "offset:"
+
+// cursorOf returns the cursor of the node at an offset of a connection
+func cursorOf(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+// connection returns a connection of the page of nodes starting at offset,
+// of total nodes
+func connection(nodes []interface{}, offset, total int) map[string]interface{} {
+	edges := make([]interface{}, 0, len(nodes))
+	for i, node := range nodes {
+		edges = append(edges, map[string]interface{}{"cursor": cursorOf(offset + i), "node": node})
+	}
+	info := map[string]interface{}{
+		"hasNextPage":     offset+len(nodes) < total,
+		"hasPreviousPage": offset > 0,
+		"startCursor":     nil,
+		"endCursor":       nil,
+	}
+	if len(nodes) != 0 {
+		info["startCursor"] = cursorOf(offset)
+		info["endCursor"] = cursorOf(offset + len(nodes) - 1)
+	}
+	return map[string]interface{}{"edges": edges, "pageInfo": info, "totalCount": total}
+}
+
+// loader loads the values of the ids asked for by the resolvers of a query
+// in batches: the resolvers get thunks of the values, the first one called
+// loading the values of all the ids asked for until then
+type loader struct {
+	load    func(ctx context.Context, ids []int64) (map[int64]interface{}, error)
+	mu      sync.Mutex
+	pending []int64
+	results map[int64]*loaded
+}
+
+// loaded is the result of the load of an id, a nil value for none
+type loaded struct {
+	value interface{}
+	err   error
+}
+
+// newLoader returns a loader of the values load returns by id
+func newLoader(load func(ctx context.Context, ids []int64) (map[int64]interface{}, error)) *loader {
+	return &loader{load: load, results: map[int64]*loaded{}}
+}
+
+// Load returns a thunk of the value of id
+func (l *loader) Load(ctx context.Context, id int64) func() (interface{}, error) {
+	l.mu.Lock()
+	l.enqueue(id)
+	l.mu.Unlock()
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		// the results may have been reset by a mutation since
+		l.enqueue(id)
+		if len(l.pending) != 0 && l.results[id] == nil {
+			ids := l.pending
+			l.pending = nil
+			values, err := l.load(ctx, ids)
+			for _, id := range ids {
+				l.results[id] = &loaded{values[id], err}
+			}
+		}
+		res := l.results[id]
+		return res.value, res.err
+	}
+}
+
+// enqueue adds id to the next batch, unless loaded or to be
+func (l *loader) enqueue(id int64) {
+	if l.results[id] != nil {
+		return
+	}
+	for _, p := range l.pending {
+		if p == id {
+			return
+		}
+	}
+	l.pending = append(l.pending, id)
+}
+
+// Prime sets the value of id, read along with others, unless already loaded
+func (l *loader) Prime(id int64, value interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.results[id] == nil {
+		l.results[id] = &loaded{value: value}
+	}
+}
+
+// Reset forgets the values loaded, once changed
+func (l *loader) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.results = map[int64]*loaded{}
+}
+
+// loaders of a query, by the id of what they load
+type loaders struct {
+	users *loader // *users.User
+	lists *loader // *todolist.TodoListSummary
+	items *loader // []*todolist.TodoItem, of a list
+	owned *loader // []*todolist.TodoListSummary, of a user
+}
+
+// loadersKey is the context key of the loaders of a query
+type loadersKey struct{}
+
+// loadersOf returns the loaders of the query of ctx
+func loadersOf(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// newLoaders returns the loaders of a query, which only loads what it finds;
+// the lists of users are primed into the loader of the lists
+func (g *GraphQL) newLoaders() *loaders {
+	l := &loaders{
+		users: newLoader(func(ctx context.Context, ids []int64) (map[int64]interface{}, error) {
+			found, err := g.s.GetUsersByID(ctx, ids)
+			values := make(map[int64]interface{}, len(found))
+			for id, user := range found {
+				values[id] = user
+			}
+			return values, err
+		}),
+		lists: newLoader(func(ctx context.Context, ids []int64) (map[int64]interface{}, error) {
+			found, err := g.c.GetTodoListSummaries(ctx, ids)
+			values := make(map[int64]interface{}, len(found))
+			for id, list := range found {
+				values[id] = list
+			}
+			return values, err
+		}),
+		items: newLoader(func(ctx context.Context, ids []int64) (map[int64]interface{}, error) {
+			found, err := g.c.GetTodoListsItems(ctx, ids)
+			values := make(map[int64]interface{}, len(found))
+			for id, items := range found {
+				values[id] = items
+			}
+			return values, err
+		}),
+	}
+	l.owned = newLoader(func(ctx context.Context, ids []int64) (map[int64]interface{}, error) {
+		found, err := g.c.GetOwnersTodoLists(ctx, ids)
+		values := make(map[int64]interface{}, len(found))
+		for id, lists := range found {
+			for _, list := range lists {
+				l.lists.Prime(list.ID, list)
+			}
+			values[id] = lists
+		}
+		return values, err
+	})
+	return l
+}
+
+// reset resets the loaders once a mutation is made
+func (l *loaders) reset() {
+	l.users.Reset()
+	l.lists.Reset()
+	l.items.Reset()
+	l.owned.Reset()
+}
+
+// resolverError is an error of a resolver, answered with the code of its
+// kind in its extensions
+type resolverError struct {
+	msg  string
+	code string
+}
+
+func (e *resolverError) Error() string {
+	return e.msg
+}
+
+// Extensions ...
+func (e *resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+// badInput returns the error of an argument which is not valid
+func badInput(msg string) error {
+	return &resolverError{msg, "BAD_USER_INPUT"}
+}
+
+// graphqlError returns the error of a resolver for an error of the core or
+// the user store, with the codes of the statuses InternalServerError answers
+// with
+func graphqlError(ctx context.Context, err error) error {
+	switch {
+	case isOneOf(err, todolist.ErrNotFound, todolist.ErrItemNotFound, users.ErrNotFound):
+		return &resolverError{err.Error(), "NOT_FOUND"}
+	case isOneOf(err, todolist.ErrInvalidMove, todolist.ErrPriority, todolist.ErrRecurrence,
+		todolist.ErrParent, todolist.ErrTag, todolist.ErrSort, todolist.ErrMissingVariable, todolist.ErrOwner):
+		return badInput(err.Error())
+	case isOneOf(err, todolist.ErrNotTemplate, todolist.ErrArchived):
+		return &resolverError{err.Error(), "FAILED_PRECONDITION"}
+	}
+	if errors.Is(err, context.Canceled) {
+		return &resolverError{err.Error(), "CANCELED"}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		logging.FromContext(ctx).Warn("request timed out", "err", err)
+		return &resolverError{"request timed out", "DEADLINE_EXCEEDED"}
+	}
+	logging.FromContext(ctx).Error("request failed", "err", err)
+	msg := "internal error"
+	if id := tracing.TraceID(ctx); id != "" {
+		msg += " (trace id " + id + ")"
+	}
+	return &resolverError{msg, "INTERNAL"}
+}
+
+// isOneOf tells whether err is, or wraps, one of targets
+func isOneOf(err error, targets ...error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
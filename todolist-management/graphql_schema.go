@@ -0,0 +1,659 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/Shivam010/go-rest-api/todolist-management/lib"
+	"github.com/Shivam010/go-rest-api/user-management/lib"
+)
+
+// newSchema returns the schema of the api, the users with the lists they own
+// and the lists with their items
+func (g *GraphQL) newSchema() (graphql.Schema, error) {
+	priority := graphql.NewEnum(graphql.EnumConfig{
+		Name: "Priority",
+		Values: graphql.EnumValueConfigMap{
+			"LOW":    &graphql.EnumValueConfig{Value: todolist.PriorityLow},
+			"NORMAL": &graphql.EnumValueConfig{Value: todolist.PriorityNormal},
+			"HIGH":   &graphql.EnumValueConfig{Value: todolist.PriorityHigh},
+			"URGENT": &graphql.EnumValueConfig{Value: todolist.PriorityUrgent},
+		},
+	})
+
+	// users, lists and items refer to each other, their fields are made once
+	// all exist
+	var user, list, item, lists *graphql.Object
+	user = graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":    userField(graphql.NewNonNull(graphql.ID), func(u *users.User) interface{} { return strconv.FormatInt(u.ID, 10) }),
+				"fname": userField(graphql.NewNonNull(graphql.String), func(u *users.User) interface{} { return u.Fname }),
+				"lname": userField(graphql.NewNonNull(graphql.String), func(u *users.User) interface{} { return u.Lname }),
+				"dob":   userField(graphql.NewNonNull(graphql.String), func(u *users.User) interface{} { return u.DOB }),
+				"email": userField(graphql.NewNonNull(graphql.String), func(u *users.User) interface{} { return u.Email }),
+				// phone numbers don't fit in the 32 bits of an Int
+				"phoneNo": userField(graphql.NewNonNull(graphql.String), func(u *users.User) interface{} { return strconv.FormatInt(u.PhoneNo, 10) }),
+				"lists": &graphql.Field{
+					Description: "The lists the user owns, archived ones and templates included, sorted by id",
+					Type:        graphql.NewNonNull(lists),
+					Args:        pageConfig(),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						first, offset, err := pageArgs(p.Args)
+						if err != nil {
+							return nil, err
+						}
+						load := loadersOf(p.Context).owned.Load(p.Context, p.Source.(*users.User).ID)
+						return func() (interface{}, error) {
+							v, err := load()
+							if err != nil {
+								return nil, graphqlError(p.Context, err)
+							}
+							owned, _ := v.([]*todolist.TodoListSummary)
+							nodes := []interface{}{}
+							for i := offset; i < len(owned) && len(nodes) < first; i++ {
+								nodes = append(nodes, owned[i])
+							}
+							return connection(nodes, offset, len(owned)), nil
+						}, nil
+					},
+				},
+			}
+		}),
+	})
+
+	item = graphql.NewObject(graphql.ObjectConfig{
+		Name: "TodoItem",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": itemField(graphql.NewNonNull(graphql.ID), func(i *todolist.TodoItem) interface{} { return strconv.FormatInt(i.ID, 10) }),
+				"list": &graphql.Field{
+					Type: graphql.NewNonNull(list),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadersOf(p.Context).lists.Load(p.Context, p.Source.(*todolist.TodoItem).ListID), nil
+					},
+				},
+				"value":     itemField(graphql.NewNonNull(graphql.String), func(i *todolist.TodoItem) interface{} { return i.Value }),
+				"completed": itemField(graphql.NewNonNull(graphql.Boolean), func(i *todolist.TodoItem) interface{} { return i.Completed }),
+				// positions are spaced apart, past the 32 bits of an Int
+				"position":    itemField(graphql.NewNonNull(graphql.Float), func(i *todolist.TodoItem) interface{} { return float64(i.Position) }),
+				"dueAt":       itemField(graphql.DateTime, func(i *todolist.TodoItem) interface{} { return timeOrNil(i.DueAt) }),
+				"priority":    itemField(graphql.NewNonNull(priority), func(i *todolist.TodoItem) interface{} { return i.Priority }),
+				"completedAt": itemField(graphql.DateTime, func(i *todolist.TodoItem) interface{} { return timeOrNil(i.CompletedAt) }),
+				"createdAt":   itemField(graphql.NewNonNull(graphql.DateTime), func(i *todolist.TodoItem) interface{} { return i.CreatedAt }),
+				"updatedAt":   itemField(graphql.NewNonNull(graphql.DateTime), func(i *todolist.TodoItem) interface{} { return i.UpdatedAt }),
+				"recurrence":  itemField(graphql.NewNonNull(graphql.String), func(i *todolist.TodoItem) interface{} { return i.Recurrence }),
+				"seriesId":    itemField(graphql.NewNonNull(graphql.ID), func(i *todolist.TodoItem) interface{} { return strconv.FormatInt(i.SeriesID, 10) }),
+				"occurrence":  itemField(graphql.NewNonNull(graphql.Int), func(i *todolist.TodoItem) interface{} { return i.Occurrence }),
+				"tags":        itemField(nonNullList(graphql.String), func(i *todolist.TodoItem) interface{} { return i.Tags }),
+				"children": itemField(nonNullList(item), func(i *todolist.TodoItem) interface{} {
+					children := make([]interface{}, 0, len(i.Children))
+					for _, child := range i.Children {
+						children = append(children, child)
+					}
+					return children
+				}),
+			}
+		}),
+	})
+
+	list = graphql.NewObject(graphql.ObjectConfig{
+		Name: "TodoList",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":             listField(graphql.NewNonNull(graphql.ID), func(l *todolist.TodoListSummary) interface{} { return strconv.FormatInt(l.ID, 10) }),
+				"name":           listField(graphql.NewNonNull(graphql.String), func(l *todolist.TodoListSummary) interface{} { return l.Name }),
+				"archived":       listField(graphql.NewNonNull(graphql.Boolean), func(l *todolist.TodoListSummary) interface{} { return l.Archived }),
+				"template":       listField(graphql.NewNonNull(graphql.Boolean), func(l *todolist.TodoListSummary) interface{} { return l.Template }),
+				"itemCount":      listField(graphql.NewNonNull(graphql.Int), func(l *todolist.TodoListSummary) interface{} { return l.ItemCount }),
+				"completedCount": listField(graphql.NewNonNull(graphql.Int), func(l *todolist.TodoListSummary) interface{} { return l.CompletedCount }),
+				"completion":     listField(graphql.NewNonNull(graphql.Float), func(l *todolist.TodoListSummary) interface{} { return l.Completion }),
+				"createdAt":      listField(graphql.NewNonNull(graphql.DateTime), func(l *todolist.TodoListSummary) interface{} { return l.CreatedAt }),
+				"updatedAt":      listField(graphql.NewNonNull(graphql.DateTime), func(l *todolist.TodoListSummary) interface{} { return l.UpdatedAt }),
+				"owner": &graphql.Field{
+					Type: user,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						owner := p.Source.(*todolist.TodoListSummary).OwnerID
+						if owner == nil {
+							return nil, nil
+						}
+						return loadersOf(p.Context).users.Load(p.Context, *owner), nil
+					},
+				},
+				"items": &graphql.Field{
+					Description: "The top level items of the list, their sub-items being their children",
+					Type:        graphql.NewNonNull(connectionOf(item)),
+					Args:        pageConfig(),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						first, offset, err := pageArgs(p.Args)
+						if err != nil {
+							return nil, err
+						}
+						load := loadersOf(p.Context).items.Load(p.Context, p.Source.(*todolist.TodoListSummary).ID)
+						return func() (interface{}, error) {
+							v, err := load()
+							if err != nil {
+								return nil, graphqlError(p.Context, err)
+							}
+							items, _ := v.([]*todolist.TodoItem)
+							nodes := []interface{}{}
+							for i := offset; i < len(items) && len(nodes) < first; i++ {
+								nodes = append(nodes, items[i])
+							}
+							return connection(nodes, offset, len(items)), nil
+						}, nil
+					},
+				},
+			}
+		}),
+	})
+
+	lists = connectionOf(list)
+
+	userInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "UserInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"fname":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"lname":   &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"dob":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"email":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"phoneNo": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+
+	var itemInput *graphql.InputObject
+	itemInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "TodoItemInput",
+		Description: "An item, its tags and children being left as they are when updated",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return graphql.InputObjectConfigFieldMap{
+				"value":      &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"completed":  &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+				"dueAt":      &graphql.InputObjectFieldConfig{Type: graphql.DateTime},
+				"priority":   &graphql.InputObjectFieldConfig{Type: priority},
+				"recurrence": &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"tags":       &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+				"children":   &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(itemInput))},
+			}
+		}),
+	})
+
+	itemUpdate := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "TodoItemUpdate",
+		Description: "The fields of an item to update, the ones left out are kept",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"value":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"completed":  &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+			"dueAt":      &graphql.InputObjectFieldConfig{Type: graphql.DateTime},
+			"clearDueAt": &graphql.InputObjectFieldConfig{Type: graphql.Boolean, Description: "Removes the due date"},
+			"priority":   &graphql.InputObjectFieldConfig{Type: priority},
+			"recurrence": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+
+	id := &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}
+	tags := &graphql.ArgumentConfig{Type: nonNullList(graphql.String)}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"user": &graphql.Field{
+				Type: user,
+				Args: graphql.FieldConfigArgument{"id": id},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+					return loadersOf(p.Context).users.Load(p.Context, id), nil
+				},
+			},
+			"users": &graphql.Field{
+				Type: graphql.NewNonNull(connectionOf(user)),
+				Args: pageConfig(),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					first, offset, err := pageArgs(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					all, err := g.s.GetAllUsers(p.Context)
+					if err != nil {
+						return nil, graphqlError(p.Context, err)
+					}
+					loaders := loadersOf(p.Context)
+					nodes := []interface{}{}
+					for i := offset; i < len(all) && len(nodes) < first; i++ {
+						loaders.users.Prime(all[i].ID, all[i])
+						nodes = append(nodes, all[i])
+					}
+					return connection(nodes, offset, len(all)), nil
+				},
+			},
+			"list": &graphql.Field{
+				Type: list,
+				Args: graphql.FieldConfigArgument{"id": id},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+					return loadersOf(p.Context).lists.Load(p.Context, id), nil
+				},
+			},
+			"lists": &graphql.Field{
+				Description: "The lists, archived ones and templates only if asked for, sorted by one of the sort keys of /lists",
+				Type:        graphql.NewNonNull(lists),
+				Args: withPageConfig(graphql.FieldConfigArgument{
+					"archived":  &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+					"templates": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+					"sort":      &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: ""},
+					"desc":      &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+				}),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					first, offset, err := pageArgs(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					opts := &todolist.ListOptions{Limit: first, Offset: offset}
+					opts.Archived, _ = p.Args["archived"].(bool)
+					opts.Templates, _ = p.Args["templates"].(bool)
+					opts.Sort, _ = p.Args["sort"].(string)
+					opts.Desc, _ = p.Args["desc"].(bool)
+					page, err := g.c.ListTodoLists(p.Context, opts)
+					if err != nil {
+						return nil, graphqlError(p.Context, err)
+					}
+					loaders := loadersOf(p.Context)
+					nodes := make([]interface{}, 0, len(page.Lists))
+					for _, l := range page.Lists {
+						loaders.lists.Prime(l.ID, l)
+						nodes = append(nodes, l)
+					}
+					return connection(nodes, offset, page.Total), nil
+				},
+			},
+			"item": &graphql.Field{
+				Type: item,
+				Args: graphql.FieldConfigArgument{"id": id},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+					i, err := g.c.GetTodoListItem(p.Context, id)
+					if errors.Is(err, todolist.ErrItemNotFound) {
+						return nil, nil
+					}
+					if err != nil {
+						return nil, graphqlError(p.Context, err)
+					}
+					return i, nil
+				},
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createUser": g.mutation(graphql.NewNonNull(user), graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(userInput)},
+			}, func(p graphql.ResolveParams) (interface{}, error) {
+				u, err := userArg(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				return g.s.CreateUser(p.Context, u)
+			}),
+			"updateUser": g.mutation(graphql.NewNonNull(user), graphql.FieldConfigArgument{
+				"id":    id,
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(userInput)},
+			}, func(p graphql.ResolveParams) (interface{}, error) {
+				u, err := userArg(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				if u.ID, err = idArg(p.Args, "id"); err != nil {
+					return nil, err
+				}
+				if err := g.s.EditUser(p.Context, u); err != nil {
+					return nil, err
+				}
+				return u, nil
+			}),
+			"deleteUser": g.mutation(graphql.NewNonNull(graphql.Boolean), graphql.FieldConfigArgument{
+				"id": id,
+			}, func(p graphql.ResolveParams) (interface{}, error) {
+				id, err := idArg(p.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+				return true, g.s.DeleteUser(p.Context, id)
+			}),
+
+			"createList": g.mutation(graphql.NewNonNull(list), graphql.FieldConfigArgument{
+				"name":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"template": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+				"ownerId":  &graphql.ArgumentConfig{Type: graphql.ID},
+				"items":    &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(itemInput))},
+			}, func(p graphql.ResolveParams) (interface{}, error) {
+				l := &todolist.TodoList{Items: itemsArg(p.Args["items"])}
+				l.Name, _ = p.Args["name"].(string)
+				l.Template, _ = p.Args["template"].(bool)
+				if _, ok := p.Args["ownerId"]; ok {
+					owner, err := idArg(p.Args, "ownerId")
+					if err != nil {
+						return nil, err
+					}
+					l.OwnerID = &owner
+				}
+				l, err := g.c.AddTodoList(p.Context, l)
+				if err != nil {
+					return nil, err
+				}
+				return &todolist.TodoListSummary{ID: l.ID, Name: l.Name, ListStats: l.ListStats}, nil
+			}),
+			"renameList": g.listMutation(list, graphql.FieldConfigArgument{
+				"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			}, func(p graphql.ResolveParams, id int64) error {
+				name, _ := p.Args["name"].(string)
+				return g.c.EditTodoListName(p.Context, id, name)
+			}),
+			"archiveList": g.listMutation(list, graphql.FieldConfigArgument{}, func(p graphql.ResolveParams, id int64) error {
+				return g.c.ArchiveTodoList(p.Context, id)
+			}),
+			"unarchiveList": g.listMutation(list, graphql.FieldConfigArgument{}, func(p graphql.ResolveParams, id int64) error {
+				return g.c.UnarchiveTodoList(p.Context, id)
+			}),
+			"deleteList": g.mutation(graphql.NewNonNull(graphql.Boolean), graphql.FieldConfigArgument{
+				"id": id,
+			}, func(p graphql.ResolveParams) (interface{}, error) {
+				id, err := idArg(p.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+				return true, g.c.DeleteTodoList(p.Context, id)
+			}),
+
+			"addItem": g.mutation(graphql.NewNonNull(item), graphql.FieldConfigArgument{
+				"listId": id,
+				"input":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(itemInput)},
+			}, func(p graphql.ResolveParams) (interface{}, error) {
+				lid, err := idArg(p.Args, "listId")
+				if err != nil {
+					return nil, err
+				}
+				return g.c.AddTodoItem(p.Context, lid, itemArg(p.Args["input"]))
+			}),
+			"updateItem": g.mutation(graphql.NewNonNull(item), graphql.FieldConfigArgument{
+				"id":    id,
+				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(itemUpdate)},
+			}, func(p graphql.ResolveParams) (interface{}, error) {
+				i, fields := itemUpdateArg(p.Args["input"])
+				var err error
+				if i.ID, err = idArg(p.Args, "id"); err != nil {
+					return nil, err
+				}
+				if len(fields) == 0 {
+					return g.c.GetTodoListItem(p.Context, i.ID)
+				}
+				if err := g.c.UpdateTodoItem(p.Context, i, fields...); err != nil {
+					return nil, err
+				}
+				return g.c.GetTodoListItem(p.Context, i.ID)
+			}),
+			"deleteItem": g.mutation(graphql.NewNonNull(graphql.Boolean), graphql.FieldConfigArgument{
+				"id":      id,
+				"cascade": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+			}, func(p graphql.ResolveParams) (interface{}, error) {
+				id, err := idArg(p.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+				cascade, _ := p.Args["cascade"].(bool)
+				return true, g.c.DeleteTodoListItem(p.Context, id, cascade)
+			}),
+			"addItemTags": g.mutation(graphql.NewNonNull(item), graphql.FieldConfigArgument{
+				"id":   id,
+				"tags": tags,
+			}, func(p graphql.ResolveParams) (interface{}, error) {
+				id, err := idArg(p.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+				return g.c.AddTodoItemTags(p.Context, id, stringsArg(p.Args["tags"]))
+			}),
+			"removeItemTags": g.mutation(graphql.NewNonNull(item), graphql.FieldConfigArgument{
+				"id":   id,
+				"tags": tags,
+			}, func(p graphql.ResolveParams) (interface{}, error) {
+				id, err := idArg(p.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+				return g.c.RemoveTodoItemTags(p.Context, id, stringsArg(p.Args["tags"]))
+			}),
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// mutation returns the field of a mutation made by resolve, the loaders of
+// the query being reset once it's made
+func (g *GraphQL) mutation(typ graphql.Output, args graphql.FieldConfigArgument, resolve graphql.FieldResolveFn) *graphql.Field {
+	return &graphql.Field{
+		Type: typ,
+		Args: args,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			v, err := resolve(p)
+			loadersOf(p.Context).reset()
+			if err != nil {
+				var re *resolverError
+				if errors.As(err, &re) {
+					return nil, err
+				}
+				return nil, graphqlError(p.Context, err)
+			}
+			return v, nil
+		},
+	}
+}
+
+// listMutation returns the field of a mutation made by change to the list
+// of the id argument, which is then answered with
+func (g *GraphQL) listMutation(list *graphql.Object, args graphql.FieldConfigArgument, change func(p graphql.ResolveParams, id int64) error) *graphql.Field {
+	args["id"] = &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}
+	return g.mutation(graphql.NewNonNull(list), args, func(p graphql.ResolveParams) (interface{}, error) {
+		id, err := idArg(p.Args, "id")
+		if err != nil {
+			return nil, err
+		}
+		if err := change(p, id); err != nil {
+			return nil, err
+		}
+		lists, err := g.c.GetTodoListSummaries(p.Context, []int64{id})
+		if err != nil {
+			return nil, err
+		}
+		if lists[id] == nil {
+			return nil, todolist.ErrNotFound
+		}
+		return lists[id], nil
+	})
+}
+
+// userField returns a field of a user, read by get
+func userField(typ graphql.Output, get func(*users.User) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: typ,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return get(p.Source.(*users.User)), nil
+		},
+	}
+}
+
+// listField returns a field of a list, read by get
+func listField(typ graphql.Output, get func(*todolist.TodoListSummary) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: typ,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return get(p.Source.(*todolist.TodoListSummary)), nil
+		},
+	}
+}
+
+// itemField returns a field of an item, read by get
+func itemField(typ graphql.Output, get func(*todolist.TodoItem) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: typ,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return get(p.Source.(*todolist.TodoItem)), nil
+		},
+	}
+}
+
+// nonNullList returns the type of a list of values of typ, neither the list
+// nor its values being null
+func nonNullList(typ graphql.Type) *graphql.NonNull {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(typ)))
+}
+
+// pageInfo is the type of the page of a connection
+var pageInfo = graphql.NewObject(graphql.ObjectConfig{
+	Name: "PageInfo",
+	Fields: graphql.Fields{
+		"hasNextPage":     &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		"hasPreviousPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		"startCursor":     &graphql.Field{Type: graphql.String},
+		"endCursor":       &graphql.Field{Type: graphql.String},
+	},
+})
+
+// connectionOf returns the type of a connection of nodes of typ, as made by
+// connection
+func connectionOf(typ *graphql.Object) *graphql.Object {
+	edge := graphql.NewObject(graphql.ObjectConfig{
+		Name: typ.Name() + "Edge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"node":   &graphql.Field{Type: graphql.NewNonNull(typ)},
+		},
+	})
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: typ.Name() + "Connection",
+		Fields: graphql.Fields{
+			"edges":      &graphql.Field{Type: nonNullList(edge)},
+			"pageInfo":   &graphql.Field{Type: graphql.NewNonNull(pageInfo)},
+			"totalCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+}
+
+// pageConfig returns the arguments of a page of a connection, read by
+// pageArgs
+func pageConfig() graphql.FieldConfigArgument {
+	return withPageConfig(graphql.FieldConfigArgument{})
+}
+
+// withPageConfig adds the arguments of a page of a connection to args
+func withPageConfig(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	args["first"] = &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: graphqlPageSize}
+	args["after"] = &graphql.ArgumentConfig{Type: graphql.String}
+	return args
+}
+
+// idArg returns the id of the argument name
+func idArg(args map[string]interface{}, name string) (int64, error) {
+	s, _ := args[name].(string)
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, badInput("invalid " + name)
+	}
+	return id, nil
+}
+
+// userArg returns the user of the input argument
+func userArg(args map[string]interface{}) (*users.User, error) {
+	in, _ := args["input"].(map[string]interface{})
+	u := &users.User{}
+	u.Fname, _ = in["fname"].(string)
+	u.Lname, _ = in["lname"].(string)
+	u.DOB, _ = in["dob"].(string)
+	u.Email, _ = in["email"].(string)
+	if phone, ok := in["phoneNo"].(string); ok && phone != "" {
+		var err error
+		if u.PhoneNo, err = strconv.ParseInt(phone, 10, 64); err != nil {
+			return nil, badInput("invalid phoneNo")
+		}
+	}
+	return u, nil
+}
+
+// itemArg returns the item of a TodoItemInput
+func itemArg(v interface{}) *todolist.TodoItem {
+	in, _ := v.(map[string]interface{})
+	i := &todolist.TodoItem{Tags: stringsArg(in["tags"]), Children: itemsArg(in["children"])}
+	i.Value, _ = in["value"].(string)
+	i.Completed, _ = in["completed"].(bool)
+	if due, ok := in["dueAt"].(time.Time); ok {
+		i.DueAt = &due
+	}
+	i.Priority, _ = in["priority"].(todolist.Priority)
+	i.Recurrence, _ = in["recurrence"].(string)
+	return i
+}
+
+// itemUpdateArg returns the item of a TodoItemUpdate with the json names of
+// its fields given
+func itemUpdateArg(v interface{}) (*todolist.TodoItem, []string) {
+	in, _ := v.(map[string]interface{})
+	i := itemArg(in)
+	fields := []string{}
+	for arg, field := range map[string]string{
+		"value": "value", "completed": "completed", "dueAt": "due_at", "priority": "priority", "recurrence": "recurrence",
+	} {
+		if _, ok := in[arg]; ok {
+			fields = append(fields, field)
+		}
+	}
+	if clear, _ := in["clearDueAt"].(bool); clear {
+		i.DueAt = nil
+		fields = append(fields, "due_at")
+	}
+	return i, fields
+}
+
+// itemsArg returns the items of a list of TodoItemInput
+func itemsArg(v interface{}) []*todolist.TodoItem {
+	in, _ := v.([]interface{})
+	items := make([]*todolist.TodoItem, 0, len(in))
+	for _, item := range in {
+		items = append(items, itemArg(item))
+	}
+	return items
+}
+
+// stringsArg returns the strings of a list argument
+func stringsArg(v interface{}) []string {
+	in, _ := v.([]interface{})
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		if s, ok := s.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// timeOrNil returns the time t points to, nil when t is
+func timeOrNil(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
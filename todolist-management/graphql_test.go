@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/dbtest"
+	todolist "github.com/Shivam010/go-rest-api/todolist-management/lib"
+	users "github.com/Shivam010/go-rest-api/user-management/lib"
+)
+
+func TestGraphQLUserListsItems(t *testing.T) {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	queries := map[string]int{}
+	db := dbtest.Open(func(query string, args []driver.Value) ([][]driver.Value, error) {
+		switch {
+		case strings.Contains(query, "FROM user_management.users ORDER BY id"):
+			queries["users"]++
+			return [][]driver.Value{
+				{int64(1), "ada", "", "", "ada@example.com", int64(1)},
+				{int64(2), "alan", "", "", "alan@example.com", int64(2)},
+			}, nil
+		case strings.Contains(query, "l.owner_id = ANY($1)"):
+			queries["lists"]++
+			return [][]driver.Value{
+				{int64(10), "groceries", false, false, int64(1), int64(0), created, created, int64(1)},
+				{int64(11), "chores", false, false, int64(0), int64(0), created, created, int64(1)},
+				{int64(12), "books", false, false, int64(1), int64(1), created, created, int64(2)},
+			}, nil
+		case strings.Contains(query, "WHERE list_id = ANY($1)"):
+			queries["items"]++
+			return [][]driver.Value{
+				{int64(100), int64(10), "milk", false, int64(1024), nil, "normal", nil, created, created, "", int64(100), int64(1), int64(0), []byte("{}")},
+				{int64(101), int64(12), "dune", true, int64(1024), nil, "normal", created, created, created, "", int64(101), int64(1), int64(0), []byte("{}")},
+			}, nil
+		}
+		queries[query]++
+		return nil, nil
+	})
+	g, err := NewGraphQL(todolist.NewCore(db), users.NewStore(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"query": "{ users(first: 5) { edges { node { fname lists(first: 5) { totalCount edges { node { name owner { fname } items(first: 10) { edges { node { value } } } } } } } } } }"}`
+	w := httptest.NewRecorder()
+	g.Query(w, httptest.NewRequest("POST", "/graphql", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body)
+	}
+	res := struct {
+		Data struct {
+			Users struct {
+				Edges []struct {
+					Node struct {
+						Fname string
+						Lists struct {
+							TotalCount int
+							Edges      []struct {
+								Node struct {
+									Name  string
+									Owner struct{ Fname string }
+									Items struct {
+										Edges []struct{ Node struct{ Value string } }
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+		Errors []interface{}
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil || len(res.Errors) != 0 {
+		t.Fatalf("body = %s, %v", w.Body, err)
+	}
+
+	got := []string{}
+	for _, u := range res.Data.Users.Edges {
+		for _, l := range u.Node.Lists.Edges {
+			values := []string{}
+			for _, i := range l.Node.Items.Edges {
+				values = append(values, i.Node.Value)
+			}
+			got = append(got, fmt.Sprintf("%s/%s(%s)%v", u.Node.Fname, l.Node.Name, l.Node.Owner.Fname, values))
+		}
+	}
+	want := "[ada/groceries(ada)[milk] ada/chores(ada)[] alan/books(alan)[dune]]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("users = %v, want %s", got, want)
+	}
+	// the lists of the users and their items are read a query each, the
+	// owners being loaded along with the users
+	if len(queries) != 3 || queries["users"] != 1 || queries["lists"] != 1 || queries["items"] != 1 {
+		t.Errorf("queries = %v, want the users, their lists and their items read once", queries)
+	}
+}
+
+func TestGraphQLError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{todolist.ErrNotFound, "NOT_FOUND"},
+		{fmt.Errorf("getting user: %w", users.ErrNotFound), "NOT_FOUND"},
+		{fmt.Errorf("creating list: %w", todolist.ErrOwner), "BAD_USER_INPUT"},
+		{fmt.Errorf("instantiating: %w", todolist.ErrNotTemplate), "FAILED_PRECONDITION"},
+		{fmt.Errorf("query: %w", context.DeadlineExceeded), "DEADLINE_EXCEEDED"},
+		{fmt.Errorf("connection refused"), "INTERNAL"},
+	}
+	for _, tt := range tests {
+		err := graphqlError(context.Background(), tt.err).(*resolverError)
+		if err.code != tt.code {
+			t.Errorf("graphqlError(%v) code = %s, want %s", tt.err, err.code, tt.code)
+		}
+	}
+}
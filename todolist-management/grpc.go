@@ -0,0 +1,419 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Shivam010/go-rest-api/logging"
+	"github.com/Shivam010/go-rest-api/proto/todolistpb"
+	"github.com/Shivam010/go-rest-api/todolist-management/lib"
+	"github.com/Shivam010/go-rest-api/tracing"
+)
+
+// TodoListServer serves the gRPC API of the todolist core
+type TodoListServer struct {
+	todolistpb.UnimplementedTodoListServiceServer
+	c *todolist.Core
+}
+
+// NewTodoListServer ...
+func NewTodoListServer(c *todolist.Core) *TodoListServer {
+	return &TodoListServer{c: c}
+}
+
+// Register registers the server on gs
+func (s *TodoListServer) Register(gs *grpc.Server) {
+	todolistpb.RegisterTodoListServiceServer(gs, s)
+}
+
+// AddTodoList ...
+func (s *TodoListServer) AddTodoList(ctx context.Context, req *todolistpb.AddTodoListRequest) (*todolistpb.TodoList, error) {
+	list := &todolist.TodoList{Name: req.Name, Items: itemsFromProto(req.Items)}
+	list.Template = req.Template
+	list, err := s.c.AddTodoList(ctx, list)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return listToProto(list), nil
+}
+
+// GetTodoList ...
+func (s *TodoListServer) GetTodoList(ctx context.Context, req *todolistpb.GetTodoListRequest) (*todolistpb.TodoList, error) {
+	list, err := s.c.GetTodoList(ctx, req.Id, tagFilterFromProto(req.Filter))
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return listToProto(list), nil
+}
+
+// ListTodoLists returns a page of the lists, 20 by default and at most 100
+func (s *TodoListServer) ListTodoLists(ctx context.Context, req *todolistpb.ListTodoListsRequest) (*todolistpb.ListTodoListsResponse, error) {
+	opts := &todolist.ListOptions{
+		Limit:     int(req.Limit),
+		Offset:    int(req.Offset),
+		Sort:      req.Sort,
+		Desc:      req.Desc,
+		Archived:  req.Archived,
+		Templates: req.Templates,
+	}
+	if opts.Limit == 0 {
+		opts.Limit = 20
+	}
+	if opts.Limit < 1 || opts.Limit > 100 {
+		return nil, status.Error(codes.InvalidArgument, "invalid limit")
+	}
+	if opts.Offset < 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid offset")
+	}
+	page, err := s.c.ListTodoLists(ctx, opts)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	res := &todolistpb.ListTodoListsResponse{Total: int32(page.Total), Limit: int32(page.Limit), Offset: int32(page.Offset)}
+	for _, list := range page.Lists {
+		res.Lists = append(res.Lists, statsToProto(list.ID, list.Name, &list.ListStats))
+	}
+	return res, nil
+}
+
+// EditTodoListName ...
+func (s *TodoListServer) EditTodoListName(ctx context.Context, req *todolistpb.EditTodoListNameRequest) (*emptypb.Empty, error) {
+	if err := s.c.EditTodoListName(ctx, req.Id, req.Name); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// DeleteTodoList ...
+func (s *TodoListServer) DeleteTodoList(ctx context.Context, req *todolistpb.DeleteTodoListRequest) (*emptypb.Empty, error) {
+	if err := s.c.DeleteTodoList(ctx, req.Id); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ArchiveTodoList ...
+func (s *TodoListServer) ArchiveTodoList(ctx context.Context, req *todolistpb.ArchiveTodoListRequest) (*emptypb.Empty, error) {
+	if err := s.c.ArchiveTodoList(ctx, req.Id); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// UnarchiveTodoList ...
+func (s *TodoListServer) UnarchiveTodoList(ctx context.Context, req *todolistpb.UnarchiveTodoListRequest) (*emptypb.Empty, error) {
+	if err := s.c.UnarchiveTodoList(ctx, req.Id); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// CloneTodoList ...
+func (s *TodoListServer) CloneTodoList(ctx context.Context, req *todolistpb.CloneTodoListRequest) (*todolistpb.TodoList, error) {
+	opts := &todolist.CloneOptions{Name: req.Name, ResetCompleted: req.ResetCompleted, Template: req.Template}
+	list, err := s.c.CloneTodoList(ctx, req.Id, opts)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return listToProto(list), nil
+}
+
+// InstantiateTemplate ...
+func (s *TodoListServer) InstantiateTemplate(ctx context.Context, req *todolistpb.InstantiateTemplateRequest) (*todolistpb.TodoList, error) {
+	list, err := s.c.InstantiateTemplate(ctx, req.Id, req.Name, req.Variables)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return listToProto(list), nil
+}
+
+// WatchTodoList streams the events of a list, starting with a reset event
+// when the ones following last_event_id were lost; the stream fails with
+// Unavailable when the client falls too far behind, to be watched again
+// from its last event
+func (s *TodoListServer) WatchTodoList(req *todolistpb.WatchTodoListRequest, stream todolistpb.TodoListService_WatchTodoListServer) error {
+	ctx := stream.Context()
+	sub, replay, err := s.c.SubscribeTodoList(ctx, req.Id, req.LastEventId)
+	if err != nil && err != todolist.ErrEventsLost {
+		return grpcError(ctx, err)
+	}
+	defer sub.Close()
+	if errors.Is(err, todolist.ErrEventsLost) {
+		if err := stream.Send(&todolistpb.Event{Type: "reset", ListId: req.Id}); err != nil {
+			return err
+		}
+	}
+	for _, e := range replay {
+		if err := stream.Send(eventToProto(e)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.Unavailable, todolist.ErrEventsLost.Error())
+			}
+			if err := stream.Send(eventToProto(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// AddTodoItem ...
+func (s *TodoListServer) AddTodoItem(ctx context.Context, req *todolistpb.AddTodoItemRequest) (*todolistpb.TodoItem, error) {
+	item, err := s.c.AddTodoItem(ctx, req.ListId, itemFromProto(req.Item))
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return itemToProto(item), nil
+}
+
+// GetTodoItem ...
+func (s *TodoListServer) GetTodoItem(ctx context.Context, req *todolistpb.GetTodoItemRequest) (*todolistpb.TodoItem, error) {
+	item, err := s.c.GetTodoListItem(ctx, req.Id)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return itemToProto(item), nil
+}
+
+// UpdateTodoItem ...
+func (s *TodoListServer) UpdateTodoItem(ctx context.Context, req *todolistpb.UpdateTodoItemRequest) (*emptypb.Empty, error) {
+	if err := s.c.UpdateTodoItem(ctx, itemFromProto(req.Item), req.UpdateMask.GetPaths()...); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// UpdateTodoSeries ...
+func (s *TodoListServer) UpdateTodoSeries(ctx context.Context, req *todolistpb.UpdateTodoSeriesRequest) (*emptypb.Empty, error) {
+	if err := s.c.UpdateTodoSeries(ctx, req.SeriesId, itemFromProto(req.Item)); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// DeleteTodoItem ...
+func (s *TodoListServer) DeleteTodoItem(ctx context.Context, req *todolistpb.DeleteTodoItemRequest) (*emptypb.Empty, error) {
+	if err := s.c.DeleteTodoListItem(ctx, req.Id, req.Cascade); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// MoveTodoItem ...
+func (s *TodoListServer) MoveTodoItem(ctx context.Context, req *todolistpb.MoveTodoItemRequest) (*todolistpb.TodoItem, error) {
+	move := &todolist.ItemMove{ListID: req.ListId, Before: req.Before, After: req.After, ParentID: req.ParentId}
+	item, err := s.c.MoveTodoItem(ctx, req.Id, move)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return itemToProto(item), nil
+}
+
+// AddTodoItemTags ...
+func (s *TodoListServer) AddTodoItemTags(ctx context.Context, req *todolistpb.TodoItemTagsRequest) (*todolistpb.TodoItem, error) {
+	item, err := s.c.AddTodoItemTags(ctx, req.Id, req.Tags)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return itemToProto(item), nil
+}
+
+// RemoveTodoItemTags ...
+func (s *TodoListServer) RemoveTodoItemTags(ctx context.Context, req *todolistpb.TodoItemTagsRequest) (*todolistpb.TodoItem, error) {
+	item, err := s.c.RemoveTodoItemTags(ctx, req.Id, req.Tags)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return itemToProto(item), nil
+}
+
+// GetItemsByTags ...
+func (s *TodoListServer) GetItemsByTags(ctx context.Context, req *todolistpb.GetItemsByTagsRequest) (*todolistpb.TodoItems, error) {
+	filter := tagFilterFromProto(req.Filter)
+	if filter == nil {
+		return nil, status.Error(codes.InvalidArgument, "tag is required")
+	}
+	items, err := s.c.GetItemsByTags(ctx, filter, req.Archived)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &todolistpb.TodoItems{Items: itemsToProto(items)}, nil
+}
+
+// GetOverdueItems ...
+func (s *TodoListServer) GetOverdueItems(ctx context.Context, req *todolistpb.GetOverdueItemsRequest) (*todolistpb.TodoItems, error) {
+	items, err := s.c.GetOverdueItems(ctx, time.Now(), req.Archived)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &todolistpb.TodoItems{Items: itemsToProto(items)}, nil
+}
+
+// GetItemsDueBetween ...
+func (s *TodoListServer) GetItemsDueBetween(ctx context.Context, req *todolistpb.GetItemsDueBetweenRequest) (*todolistpb.TodoItems, error) {
+	if req.From == nil || req.To == nil {
+		return nil, status.Error(codes.InvalidArgument, "from and to are required")
+	}
+	items, err := s.c.GetItemsDueBetween(ctx, req.From.AsTime(), req.To.AsTime(), req.Archived)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &todolistpb.TodoItems{Items: itemsToProto(items)}, nil
+}
+
+// grpcError returns the status of an error of the core, with the codes of
+// the statuses InternalServerError answers with
+func grpcError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, todolist.ErrNotFound), errors.Is(err, todolist.ErrItemNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, todolist.ErrInvalidMove), errors.Is(err, todolist.ErrPriority),
+		errors.Is(err, todolist.ErrRecurrence), errors.Is(err, todolist.ErrParent),
+		errors.Is(err, todolist.ErrTag), errors.Is(err, todolist.ErrSort),
+		errors.Is(err, todolist.ErrMissingVariable):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, todolist.ErrNotTemplate), errors.Is(err, todolist.ErrArchived):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		logging.FromContext(ctx).Warn("request timed out", "err", err)
+		return status.Error(codes.DeadlineExceeded, "request timed out")
+	}
+	logging.FromContext(ctx).Error("request failed", "err", err)
+	msg := "internal error"
+	if id := tracing.TraceID(ctx); id != "" {
+		msg += " (trace id " + id + ")"
+	}
+	return status.Error(codes.Internal, msg)
+}
+
+// timestampOf returns the timestamp of t, nil when t is
+func timestampOf(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// timeOf returns the time of ts, nil when ts is
+func timeOf(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// itemToProto converts an item, and its children
+func itemToProto(item *todolist.TodoItem) *todolistpb.TodoItem {
+	return &todolistpb.TodoItem{
+		Id:          item.ID,
+		ListId:      item.ListID,
+		Value:       item.Value,
+		Completed:   item.Completed,
+		Position:    item.Position,
+		DueAt:       timestampOf(item.DueAt),
+		Priority:    string(item.Priority),
+		CompletedAt: timestampOf(item.CompletedAt),
+		CreatedAt:   timestamppb.New(item.CreatedAt),
+		UpdatedAt:   timestamppb.New(item.UpdatedAt),
+		Recurrence:  item.Recurrence,
+		SeriesId:    item.SeriesID,
+		Occurrence:  int32(item.Occurrence),
+		ParentId:    item.ParentID,
+		Children:    itemsToProto(item.Children),
+		Tags:        item.Tags,
+	}
+}
+
+// itemsToProto converts items
+func itemsToProto(items []*todolist.TodoItem) []*todolistpb.TodoItem {
+	out := make([]*todolistpb.TodoItem, 0, len(items))
+	for _, item := range items {
+		out = append(out, itemToProto(item))
+	}
+	return out
+}
+
+// itemFromProto converts the fields of an item written by the clients, and
+// its children
+func itemFromProto(pb *todolistpb.TodoItem) *todolist.TodoItem {
+	if pb == nil {
+		return &todolist.TodoItem{}
+	}
+	return &todolist.TodoItem{
+		ID:         pb.Id,
+		Value:      pb.Value,
+		Completed:  pb.Completed,
+		DueAt:      timeOf(pb.DueAt),
+		Priority:   todolist.Priority(pb.Priority),
+		Recurrence: pb.Recurrence,
+		ParentID:   pb.ParentId,
+		Children:   itemsFromProto(pb.Children),
+		Tags:       pb.Tags,
+	}
+}
+
+// itemsFromProto converts items written by the clients
+func itemsFromProto(pbs []*todolistpb.TodoItem) []*todolist.TodoItem {
+	items := make([]*todolist.TodoItem, 0, len(pbs))
+	for _, pb := range pbs {
+		items = append(items, itemFromProto(pb))
+	}
+	return items
+}
+
+// listToProto converts a list with its items
+func listToProto(list *todolist.TodoList) *todolistpb.TodoList {
+	pb := statsToProto(list.ID, list.Name, &list.ListStats)
+	pb.Items = itemsToProto(list.Items)
+	return pb
+}
+
+// statsToProto converts a list without its items
+func statsToProto(id int64, name string, stats *todolist.ListStats) *todolistpb.TodoList {
+	return &todolistpb.TodoList{
+		Id:             id,
+		Name:           name,
+		Archived:       stats.Archived,
+		Template:       stats.Template,
+		ItemCount:      int32(stats.ItemCount),
+		CompletedCount: int32(stats.CompletedCount),
+		Completion:     stats.Completion,
+		CreatedAt:      timestamppb.New(stats.CreatedAt),
+		UpdatedAt:      timestamppb.New(stats.UpdatedAt),
+	}
+}
+
+// eventToProto converts an event of a list
+func eventToProto(e *todolist.Event) *todolistpb.Event {
+	pb := &todolistpb.Event{Id: e.ID, Type: e.Type, ListId: e.ListID, ItemId: e.ItemID, Name: e.Name, At: timestamppb.New(e.At)}
+	if e.Item != nil {
+		pb.Item = itemToProto(e.Item)
+	}
+	return pb
+}
+
+// tagFilterFromProto converts a tag filter, nil without tags
+func tagFilterFromProto(pb *todolistpb.TagFilter) *todolist.TagFilter {
+	if pb == nil || len(pb.Tags) == 0 {
+		return nil
+	}
+	return &todolist.TagFilter{Tags: pb.Tags, Any: pb.Any}
+}
@@ -1,13 +1,24 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/Shivam010/go-rest-api/auth"
+	"github.com/Shivam010/go-rest-api/httperr"
+	"github.com/Shivam010/go-rest-api/idempotency"
+	"github.com/Shivam010/go-rest-api/logging"
+	"github.com/Shivam010/go-rest-api/metrics"
+	"github.com/Shivam010/go-rest-api/ratelimit"
 	"github.com/Shivam010/go-rest-api/todolist-management/lib"
+	"github.com/Shivam010/go-rest-api/tracing"
 )
 
 // a generic empty struct to return a empty JSON object {} in response
@@ -22,26 +33,127 @@ const (
 	dbName     = "test"
 )
 
+// database connection pool limits
+const (
+	dbMaxOpenConns    = 25
+	dbMaxIdleConns    = 5
+	dbConnMaxLifetime = 30 * time.Minute
+)
+
+// read cache limits, the values are cached for a short while since other
+// instances of the service don't purge the cache of this one
+const (
+	cacheSize = 1024
+	cacheTTL  = 30 * time.Second
+)
+
+// idempotencyTTL is how long the response of a request is replayed to its
+// retries
+const idempotencyTTL = 24 * time.Hour
+
+// eventsPing is the interval of the comments keeping an event stream open
+// through the proxies
+const eventsPing = 15 * time.Second
+
+// GraphQL limits, the page sizes of the connections and the most a query may
+// cost, a field costing one and the fields of a connection once for every
+// node of a page
+const (
+	graphqlPageSize      = 20
+	graphqlMaxPageSize   = 100
+	graphqlMaxComplexity = 5000
+)
+
 // InternalServerError is a generic internal server error handler
-func InternalServerError(w http.ResponseWriter, err error) {
-	if err == todolist.ErrNotFound || err == todolist.ErrItemNotFound {
-		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+func InternalServerError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case isAny(err, todolist.ErrNotFound, todolist.ErrItemNotFound):
+		httperr.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case isAny(err, todolist.ErrInvalidMove, todolist.ErrPriority, todolist.ErrRecurrence,
+		todolist.ErrParent, todolist.ErrTag, todolist.ErrSort,
+		todolist.ErrNotTemplate, todolist.ErrMissingVariable, todolist.ErrOwner):
+		httperr.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	case errors.Is(err, todolist.ErrArchived):
+		httperr.Error(w, err.Error(), http.StatusConflict)
 		return
 	}
-	http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
-	log.Println(err)
+	if errors.Is(err, context.Canceled) {
+		// the client went away, there is no one to answer to
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		httperr.Error(w, "503 Request Timed Out"+traceRef(r), http.StatusServiceUnavailable)
+		logging.FromContext(r.Context()).Warn("request timed out", "err", err)
+		return
+	}
+	httperr.Error(w, "500 Internal Server Error"+traceRef(r), http.StatusInternalServerError)
+	logging.FromContext(r.Context()).Error("request failed", "err", err)
 	return
 }
 
+// isAny reports whether err is one of targets, wrapped or not
+func isAny(err error, targets ...error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// traceRef returns the reference to the trace of a request given in its
+// server errors
+func traceRef(r *http.Request) string {
+	if id := tracing.TraceID(r.Context()); id != "" {
+		return " (trace id " + id + ")"
+	}
+	return ""
+}
+
 // ReturnJSONEncoded is a generic response writer for interfaces in JSON content-type
-func ReturnJSONEncoded(w http.ResponseWriter, v interface{}) {
+func ReturnJSONEncoded(w http.ResponseWriter, r *http.Request, v interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(v); err != nil {
-		InternalServerError(w, err)
+		InternalServerError(w, r, err)
 		return
 	}
 }
 
+// TagFilter returns the filter of the ?tag= query parameters, matching items
+// having all of them or, for ?match=any, any of them; nil without tags
+func TagFilter(r *http.Request) *todolist.TagFilter {
+	q := r.URL.Query()
+	if len(q["tag"]) == 0 {
+		return nil
+	}
+	return &todolist.TagFilter{Tags: q["tag"], Any: q.Get("match") == "any"}
+}
+
+// Include tells if what is left out by default is asked for, with
+// ?include=archived,templates
+func Include(r *http.Request, what string) bool {
+	for _, include := range r.URL.Query()["include"] {
+		for _, v := range strings.Split(include, ",") {
+			if v == what {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WriteEvent writes an event of a list in the Server-Sent Events format
+func WriteEvent(w io.Writer, e *todolist.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
+
 // RequestHandlerFunc is the type defined to use the http Handler Function externally
 type RequestHandlerFunc func(http.ResponseWriter, *http.Request)
 
@@ -53,30 +165,84 @@ func Wrapper(fn RequestHandlerFunc, mds ...func(RequestHandlerFunc) RequestHandl
 	return fn
 }
 
-// BasicAuthentication middleware of Basic Auth
+// Authorized tells if the Basic Auth credentials of a request, over HTTP or
+// gRPC, are the ones of the api
+func Authorized(user, pass string) bool {
+	return user == "mavis" && pass == "shivam"
+}
+
+// BasicAuthentication middleware of Basic Auth, the user of the verified
+// credentials being the principal of the request
 func BasicAuthentication(req RequestHandlerFunc) RequestHandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("WWW-Authenticate", `Basic Realm: "Restricted"`)
 		user, pass, ok := r.BasicAuth()
-		if !ok || (ok && (user != "mavis" || pass != "shivam")) {
-			http.Error(w, "Unauthorized Access", http.StatusUnauthorized)
+		if !ok || !Authorized(user, pass) {
+			httperr.Error(w, "Unauthorized Access", http.StatusUnauthorized)
 			return
 		}
-		req(w, r)
+		req(w, r.WithContext(auth.WithPrincipal(r.Context(), user)))
+	}
+}
+
+// Timeout middleware cancels the context of the request, and so the SQL run
+// for it, after d
+func Timeout(d time.Duration) func(RequestHandlerFunc) RequestHandlerFunc {
+	return func(req RequestHandlerFunc) RequestHandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			req(w, r.WithContext(ctx))
+		}
+	}
+}
+
+// Instrument middleware records the metrics of the request
+func Instrument(req RequestHandlerFunc) RequestHandlerFunc {
+	return RequestHandlerFunc(metrics.Instrument(http.HandlerFunc(req)))
+}
+
+// RateLimit middleware limits the rate of the requests of every client, listed
+// before BasicAuthentication it runs once the user is authenticated
+func RateLimit(l *ratelimit.Limiter) func(RequestHandlerFunc) RequestHandlerFunc {
+	return func(req RequestHandlerFunc) RequestHandlerFunc {
+		return RequestHandlerFunc(l.Handler(http.HandlerFunc(req)))
+	}
+}
+
+// IPRateLimit middleware limits the rate of the requests of every client IP,
+// listed after BasicAuthentication it runs before the user is authenticated,
+// counting the requests whose credentials are refused
+func IPRateLimit(l *ratelimit.Limiter) func(RequestHandlerFunc) RequestHandlerFunc {
+	return func(req RequestHandlerFunc) RequestHandlerFunc {
+		return RequestHandlerFunc(l.IPHandler(http.HandlerFunc(req)))
+	}
+}
+
+// Idempotent middleware replays the response of a POST request to its
+// retries with the same Idempotency-Key, listed before BasicAuthentication
+// the keys are those of the authenticated user, the routes without it
+// scoping the keys by the payload of the requests
+func Idempotent(k *idempotency.Keeper) func(RequestHandlerFunc) RequestHandlerFunc {
+	return func(req RequestHandlerFunc) RequestHandlerFunc {
+		return RequestHandlerFunc(k.Handler(http.HandlerFunc(req)))
 	}
 }
 
-// DatabaseConnection returns a database connection setup
+// DatabaseConnection returns a database connection setup, after checking the
+// database can be reached
 func DatabaseConnection() (*sql.DB, error) {
 	dbinfo := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", dbHost, dbPort, dbUser, dbPassword, dbName)
-	db, err := sql.Open("postgres", dbinfo)
+	db, err := tracing.OpenDB("postgres", dbinfo)
 	if err != nil {
-		log.Fatalf("db connection error: %v", err)
-		return nil, err
+		return nil, fmt.Errorf("db connection: %w", err)
 	}
+	db.SetMaxOpenConns(dbMaxOpenConns)
+	db.SetMaxIdleConns(dbMaxIdleConns)
+	db.SetConnMaxLifetime(dbConnMaxLifetime)
 	if err := db.Ping(); err != nil {
-		log.Fatalf("db ping error: %v", err)
-		return nil, err
+		db.Close()
+		return nil, fmt.Errorf("db ping: %w", err)
 	}
-	return db, err
+	return db, nil
 }
@@ -1,42 +1,251 @@
 package todolist
 
 import (
+	"context"
 	"database/sql"
 	"errors"
-	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/cache"
+	"github.com/lib/pq"
 )
 
 // Generic error messages
 var (
 	ErrNotFound     = errors.New("list not found")
 	ErrItemNotFound = errors.New("item not found")
+	ErrInvalidMove  = errors.New("invalid move anchor")
+	ErrPriority     = errors.New("invalid item priority")
+	ErrParent       = errors.New("invalid parent item")
+	ErrArchived     = errors.New("list is archived")
+	ErrOwner        = errors.New("invalid list owner")
 )
 
+// positionGap is the distance left between the positions of adjacent items,
+// so that an item can usually be moved by updating its own row only
+const positionGap = 1024
+
+// Clock tells the time to the Core, it can be replaced to control time in tests
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
 // Core ...
 type Core struct {
-	db *sql.DB
+	db     *sql.DB
+	clock  Clock
+	cache  cache.Cache
+	events *Broker
 }
 
+// eventHistory is the number of events kept for the clients reconnecting
+const eventHistory = 1024
+
 // NewCore implements Todo List Management Core Logic
 func NewCore(db *sql.DB) *Core {
-	return &Core{db}
+	return &Core{db, systemClock{}, nil, NewBroker(eventHistory)}
 }
 
-// Ex create user
-func (c *Core) Ex(name string) error {
-	if err := c.db.Ping(); err == nil {
-		fmt.Println("pinging")
-		return err
+// SetCache makes the core cache the lists and items it reads, every change
+// made through the core purging the cache; a nil cache caches nothing
+func (c *Core) SetCache(cache cache.Cache) {
+	c.cache = cache
+}
+
+// invalidate purges the cache once a change has been made
+func (c *Core) invalidate() {
+	if c.cache != nil {
+		c.cache.Purge()
+	}
+}
+
+// SetClock replaces the clock used by the core for scheduling
+func (c *Core) SetClock(clock Clock) {
+	c.clock = clock
+}
+
+// Priority of a todo item
+type Priority string
+
+// Item priorities, an item without one is of PriorityNormal
+const (
+	PriorityLow    Priority = "low"
+	PriorityNormal Priority = "normal"
+	PriorityHigh   Priority = "high"
+	PriorityUrgent Priority = "urgent"
+)
+
+// valid checks the priority and defaults an empty one to normal
+func (p *Priority) valid() error {
+	switch *p {
+	case "":
+		*p = PriorityNormal
+	case PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent:
+	default:
+		return ErrPriority
 	}
-	fmt.Println("yes", name)
 	return nil
 }
 
 // TodoItem ...
 type TodoItem struct {
-	ID        int64  `json:"id"`
-	Value     string `json:"value"`
-	Completed bool   `json:"completed"`
+	ID          int64       `json:"id"`
+	ListID      int64       `json:"list_id"`
+	Value       string      `json:"value"`
+	Completed   bool        `json:"completed"`
+	Position    int64       `json:"position"`
+	DueAt       *time.Time  `json:"due_at"`
+	Priority    Priority    `json:"priority"`
+	CompletedAt *time.Time  `json:"completed_at"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	Recurrence  string      `json:"recurrence"`
+	SeriesID    int64       `json:"series_id"`
+	Occurrence  int         `json:"occurrence"`
+	ParentID    int64       `json:"parent_id,omitempty"`
+	Children    []*TodoItem `json:"children,omitempty"`
+	Tags        []string    `json:"tags"`
+}
+
+// clone returns a copy of the item and of its children, the cached items
+// being shared by their readers
+func (item *TodoItem) clone() *TodoItem {
+	cp := *item
+	if item.DueAt != nil {
+		dueAt := *item.DueAt
+		cp.DueAt = &dueAt
+	}
+	if item.CompletedAt != nil {
+		completedAt := *item.CompletedAt
+		cp.CompletedAt = &completedAt
+	}
+	cp.Tags = slices.Clone(item.Tags)
+	if item.Children != nil {
+		cp.Children = make([]*TodoItem, len(item.Children))
+		for i, child := range item.Children {
+			cp.Children[i] = child.clone()
+		}
+	}
+	return &cp
+}
+
+// valid checks the fields of an item given by a client, and of its children
+func (item *TodoItem) valid() error {
+	if err := item.Priority.valid(); err != nil {
+		return err
+	}
+	if _, err := ParseRecurrence(item.Recurrence); err != nil {
+		return err
+	}
+	tags, err := normalizeTags(item.Tags)
+	if err != nil {
+		return err
+	}
+	item.Tags = tags
+	for _, child := range item.Children {
+		if err := child.valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// merge keeps the fields of prev in an update of the item but for the ones
+// named in fields, by their json names, all of them being named when none is
+func (item *TodoItem) merge(prev *TodoItem, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	named := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		named[field] = true
+	}
+	if !named["value"] {
+		item.Value = prev.Value
+	}
+	if !named["completed"] {
+		item.Completed = prev.Completed
+	}
+	if !named["due_at"] {
+		item.DueAt = prev.DueAt
+	}
+	if !named["priority"] {
+		item.Priority = prev.Priority
+	}
+	if !named["recurrence"] {
+		item.Recurrence = prev.Recurrence
+	}
+}
+
+// itemColumns are the todo_items columns read by scanItem, in order; the
+// series of an item is the item itself until it recurs
+const itemColumns = `id, list_id, value, completed, position, due_at, priority, completed_at, created_at, updated_at, recurrence, COALESCE(series_id, id), occurrence, COALESCE(parent_id, 0), ` + itemTags
+
+// itemTags selects the sorted tag names of the todo_items row
+const itemTags = `ARRAY(SELECT t.name FROM todolist_management.item_tags it JOIN todolist_management.tags t ON t.id = it.tag_id
+	WHERE it.item_id = todolist_management.todo_items.id ORDER BY t.name)`
+
+// scanner is implemented by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// checkList returns ErrNotFound for a missing list and ErrArchived for an
+// archived one, which can't be written to
+func checkList(ctx context.Context, q querier, lid int64) error {
+	const query = `SELECT archived FROM todolist_management.todo_lists WHERE id = $1 FOR SHARE`
+	archived := false
+	if err := q.QueryRowContext(ctx, query, lid).Scan(&archived); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		return err
+	}
+	if archived {
+		return ErrArchived
+	}
+	return nil
+}
+
+// checkSeries returns ErrArchived when one of the incomplete occurrences of
+// a series is in an archived list, like checkList
+func checkSeries(ctx context.Context, tx *sql.Tx, sid int64) error {
+	const query = `SELECT archived FROM todolist_management.todo_lists
+		WHERE id IN (SELECT list_id FROM todolist_management.todo_items WHERE COALESCE(series_id, id) = $1 AND completed IS NOT TRUE) FOR SHARE`
+	rows, err := tx.QueryContext(ctx, query, sid)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		archived := false
+		if err := rows.Scan(&archived); err != nil {
+			return err
+		}
+		if archived {
+			return ErrArchived
+		}
+	}
+	return rows.Err()
+}
+
+// scanItem reads the itemColumns of a row into item
+func scanItem(row scanner, item *TodoItem) error {
+	return row.Scan(&item.ID, &item.ListID, &item.Value, &item.Completed, &item.Position, &item.DueAt, &item.Priority, &item.CompletedAt, &item.CreatedAt, &item.UpdatedAt,
+		&item.Recurrence, &item.SeriesID, &item.Occurrence, &item.ParentID, pq.Array(&item.Tags))
 }
 
 // TodoList ...
@@ -44,124 +253,382 @@ type TodoList struct {
 	ID    int64       `json:"id"`
 	Items []*TodoItem `json:"items"`
 	Name  string      `json:"name"`
+	ListStats
 }
 
-// AddTodoList creates a todo list with it's items
-func (c *Core) AddTodoList(list *TodoList) (*TodoList, error) {
-	const listQuery = `INSERT INTO todolist_management.todo_lists (name) VALUES($1) returning id`
-	const itemQuery = `INSERT INTO todolist_management.todo_items (value, list_id, completed) VALUES($1, $2, $3) returning id`
+// clone returns a copy of the list and of its items, the cached lists being
+// shared by their readers
+func (l *TodoList) clone() *TodoList {
+	cp := *l
+	if l.OwnerID != nil {
+		owner := *l.OwnerID
+		cp.OwnerID = &owner
+	}
+	if l.Items != nil {
+		cp.Items = make([]*TodoItem, len(l.Items))
+		for i, item := range l.Items {
+			cp.Items[i] = item.clone()
+		}
+	}
+	return &cp
+}
+
+// ListStats is the metadata of a list, the counts are of all the items of
+// the list, sub-items included, and a list is updated whenever one of its
+// items is. OwnerID is the user owning the list, if any
+type ListStats struct {
+	OwnerID        *int64    `json:"owner_id"`
+	Archived       bool      `json:"archived"`
+	Template       bool      `json:"template"`
+	ItemCount      int       `json:"item_count"`
+	CompletedCount int       `json:"completed_count"`
+	Completion     float64   `json:"completion"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// setCompletion computes the completion percentage from the counts
+func (s *ListStats) setCompletion() {
+	s.Completion = 0
+	if s.ItemCount != 0 {
+		s.Completion = float64(s.CompletedCount) * 100 / float64(s.ItemCount)
+	}
+}
+
+// listColumns are the columns read by scanList from listFrom grouped by list
+const listColumns = `l.id, l.name, l.archived, l.template, COUNT(i.id), COUNT(i.id) FILTER (WHERE i.completed), l.created_at, GREATEST(l.updated_at, MAX(i.updated_at)), l.owner_id`
+
+// listFrom joins the lists to their items, if any
+const listFrom = `todolist_management.todo_lists l LEFT JOIN todolist_management.todo_items i ON i.list_id = l.id`
+
+// scanList reads the listColumns of a row
+func scanList(row scanner, id *int64, name *string, stats *ListStats) error {
+	if err := row.Scan(id, name, &stats.Archived, &stats.Template, &stats.ItemCount, &stats.CompletedCount, &stats.CreatedAt, &stats.UpdatedAt, &stats.OwnerID); err != nil {
+		return err
+	}
+	stats.setCompletion()
+	return nil
+}
+
+// AddTodoList creates a todo list with it's items, and their children; the
+// list is a template if list.Template is set, and owned by list.OwnerID if
+// set
+func (c *Core) AddTodoList(ctx context.Context, list *TodoList) (_ *TodoList, err error) {
+	ctx, end := startOperation(ctx, "AddTodoList")
+	defer end(&err)
+	defer c.invalidate()
+	const listQuery = `INSERT INTO todolist_management.todo_lists (name, template, owner_id) VALUES($1, $2, $3) returning id, archived, created_at, updated_at`
+	const itemQuery = `INSERT INTO todolist_management.todo_items (value, list_id, completed, position, due_at, priority, completed_at, recurrence, parent_id)
+		VALUES($1, $2, $3, $4, $5, $6, CASE WHEN $3 THEN now() END, $7, NULLIF($8, 0)) returning ` + itemColumns
+
+	for _, item := range list.Items {
+		if err := item.valid(); err != nil {
+			return nil, err
+		}
+	}
 
-	tx, err := c.db.Begin()
+	tx, err := c.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	if err := tx.QueryRow(listQuery, list.Name).Scan(&list.ID); err != nil {
+	list.ItemCount, list.CompletedCount = 0, 0
+	if err := tx.QueryRowContext(ctx, listQuery, list.Name, list.Template, list.OwnerID).Scan(&list.ID, &list.Archived, &list.CreatedAt, &list.UpdatedAt); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			// foreign_key_violation, of a user which doesn't exist
+			return nil, ErrOwner
+		}
 		return nil, err
 	}
 
-	stmt, err := tx.Prepare(itemQuery)
+	stmt, err := tx.PrepareContext(ctx, itemQuery)
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
 
-	for _, item := range list.Items {
-		if err := stmt.QueryRow(item.Value, list.ID, item.Completed).Scan(&item.ID); err != nil {
-			return nil, err
+	// items are positioned in the order of a depth first walk of the tree
+	pos, ids := int64(0), []int64{}
+	var insert func(items []*TodoItem, pid int64) error
+	insert = func(items []*TodoItem, pid int64) error {
+		for _, item := range items {
+			pos += positionGap
+			children, tags := item.Children, item.Tags
+			row := stmt.QueryRowContext(ctx, item.Value, list.ID, item.Completed, pos, item.DueAt, item.Priority, item.Recurrence, pid)
+			if err := scanItem(row, item); err != nil {
+				return err
+			}
+			ids = append(ids, item.ID)
+			if err := addTags(ctx, tx, item.ID, tags); err != nil {
+				return err
+			}
+			item.Tags = tags
+			list.ItemCount++
+			if item.Completed {
+				list.CompletedCount++
+			}
+			if err := insert(children, item.ID); err != nil {
+				return err
+			}
+			item.Children = children
 		}
+		return nil
+	}
+	if err := insert(list.Items, 0); err != nil {
+		return nil, err
+	}
+	event := &Event{Type: EventListCreated, ListID: list.ID, Name: list.Name}
+	if err := c.record(ctx, tx, ids, event); err != nil {
+		return nil, err
 	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
+	c.publish(event)
+	list.setCompletion()
 	return list, nil
 }
 
 // DeleteTodoList removes a todo list with it's items
-func (c *Core) DeleteTodoList(id int64) error {
-	const check = `SELECT id FROM todolist_management.todo_lists WHERE id = $1`
-	cid := int64(0)
-	if err := c.db.QueryRow(check, id).Scan(&cid); err != nil {
-		if err != sql.ErrNoRows {
-			return err
-		}
-		return ErrNotFound
-	}
-
+func (c *Core) DeleteTodoList(ctx context.Context, id int64) (err error) {
+	ctx, end := startOperation(ctx, "DeleteTodoList")
+	defer end(&err)
+	defer c.invalidate()
 	const listQuery = `DELETE FROM todolist_management.todo_lists WHERE id = $1`
-	const itemQuery = `DELETE FROM todolist_management.todo_items WHERE list_id = $1`
+	const itemQuery = `DELETE FROM todolist_management.todo_items WHERE list_id = $1 RETURNING id`
 
-	tx, err := c.db.Begin()
+	tx, err := c.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	if _, err := tx.Exec(listQuery, id); err != nil {
+	if err := checkList(ctx, tx, id); err != nil {
 		return err
 	}
-	if _, err := tx.Exec(itemQuery, id); err != nil {
+	if _, err := tx.ExecContext(ctx, listQuery, id); err != nil {
+		return err
+	}
+	ids, err := queryIDs(ctx, tx, itemQuery, id)
+	if err != nil {
+		return err
+	}
+	event := &Event{Type: EventListDeleted, ListID: id}
+	if err := c.record(ctx, tx, ids, event); err != nil {
 		return err
 	}
 
 	if err := tx.Commit(); err != nil {
 		return err
 	}
+	c.publish(event)
 	return nil
 }
 
 // EditTodoListName updates the name of the list
-func (c *Core) EditTodoListName(id int64, name string) error {
-	const check = `SELECT id FROM todolist_management.todo_lists WHERE id = $1`
-	cid := int64(0)
-	if err := c.db.QueryRow(check, id).Scan(&cid); err != nil {
-		if err != sql.ErrNoRows {
-			return err
-		}
-		return ErrNotFound
+func (c *Core) EditTodoListName(ctx context.Context, id int64, name string) (err error) {
+	ctx, end := startOperation(ctx, "EditTodoListName")
+	defer end(&err)
+	defer c.invalidate()
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := checkList(ctx, tx, id); err != nil {
+		return err
 	}
 
-	const query = `UPDATE todolist_management.todo_lists SET name = $2 WHERE id = $1`
-	if _, err := c.db.Exec(query, id, name); err != nil {
+	const query = `UPDATE todolist_management.todo_lists SET name = $2, updated_at = now() WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, id, name); err != nil {
 		return err
 	}
+	event := &Event{Type: EventListRenamed, ListID: id, Name: name}
+	if err := c.record(ctx, tx, nil, event); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	c.publish(event)
 	return nil
 }
 
-// AddTodoItem adds item to the list
-func (c *Core) AddTodoItem(lid int64, item *TodoItem) (*TodoItem, error) {
-	const check = `SELECT id FROM todolist_management.todo_lists WHERE id = $1`
-	cid := int64(0)
-	if err := c.db.QueryRow(check, lid).Scan(&cid); err != nil {
-		if err != sql.ErrNoRows {
-			return nil, err
+// AddTodoItem adds item to the list, as a child of item.ParentID when set
+func (c *Core) AddTodoItem(ctx context.Context, lid int64, item *TodoItem) (_ *TodoItem, err error) {
+	ctx, end := startOperation(ctx, "AddTodoItem")
+	defer end(&err)
+	defer c.invalidate()
+	if err := item.valid(); err != nil {
+		return nil, err
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := checkList(ctx, tx, lid); err != nil {
+		return nil, err
+	}
+	if item.ParentID != 0 {
+		const parentQuery = `SELECT id FROM todolist_management.todo_items WHERE id = $1 AND list_id = $2 FOR UPDATE`
+		cid := int64(0)
+		if err := tx.QueryRowContext(ctx, parentQuery, item.ParentID, lid).Scan(&cid); err != nil {
+			if err != sql.ErrNoRows {
+				return nil, err
+			}
+			return nil, ErrParent
 		}
-		return nil, ErrNotFound
 	}
 
-	const query = `INSERT INTO todolist_management.todo_items (value, list_id, completed) VALUES($1, $2, $3) returning id`
-	if err := c.db.QueryRow(query, item.Value, lid, item.Completed).Scan(&item.ID); err != nil {
+	const query = `INSERT INTO todolist_management.todo_items (value, list_id, completed, position, due_at, priority, completed_at, recurrence, parent_id)
+		SELECT $1, $2, $3, COALESCE(MAX(position), 0) + $4, $5, $6, CASE WHEN $3 THEN now() END, $7, NULLIF($8, 0) FROM todolist_management.todo_items WHERE list_id = $2
+		returning ` + itemColumns
+	tags := item.Tags
+	row := tx.QueryRowContext(ctx, query, item.Value, lid, item.Completed, positionGap, item.DueAt, item.Priority, item.Recurrence, item.ParentID)
+	if err := scanItem(row, item); err != nil {
+		return nil, err
+	}
+	if err := addTags(ctx, tx, item.ID, tags); err != nil {
+		return nil, err
+	}
+	item.Tags = tags
+	rolled, err := rollupCompletion(ctx, tx, item.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	events := append([]*Event{itemEvent(EventItemAdded, item)}, rollupEvents(lid, rolled)...)
+	if err := c.record(ctx, tx, nil, events...); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
+	c.publish(events...)
 	return item, nil
 }
 
-// DeleteTodoListItem removes items from the list
-func (c *Core) DeleteTodoListItem(id int64) error {
-	const query = `DELETE FROM todolist_management.todo_items WHERE id = $1`
-	if _, err := c.db.Exec(query, id); err != nil {
+// DeleteTodoListItem removes items from the list, the children of the item
+// are removed with it when cascade is set, or else moved up to its parent
+func (c *Core) DeleteTodoListItem(ctx context.Context, id int64, cascade bool) (err error) {
+	ctx, end := startOperation(ctx, "DeleteTodoListItem")
+	defer end(&err)
+	defer c.invalidate()
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	const check = `SELECT list_id, COALESCE(parent_id, 0) FROM todolist_management.todo_items WHERE id = $1 FOR UPDATE`
+	lid, pid := int64(0), int64(0)
+	if err := tx.QueryRowContext(ctx, check, id).Scan(&lid, &pid); err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		return ErrItemNotFound
+	}
+	if err := checkList(ctx, tx, lid); err != nil {
+		return err
+	}
+
+	var ids []int64
+	if cascade {
+		const query = `WITH RECURSIVE tree AS (
+				SELECT id FROM todolist_management.todo_items WHERE id = $1
+				UNION SELECT i.id FROM todolist_management.todo_items i JOIN tree ON i.parent_id = tree.id
+			)
+			DELETE FROM todolist_management.todo_items WHERE id IN (SELECT id FROM tree) RETURNING id`
+		if ids, err = queryIDs(ctx, tx, query, id); err != nil {
+			return err
+		}
+	} else {
+		const reparent = `UPDATE todolist_management.todo_items SET parent_id = NULLIF($2, 0), updated_at = now() WHERE parent_id = $1 RETURNING id`
+		if ids, err = queryIDs(ctx, tx, reparent, id, pid); err != nil {
+			return err
+		}
+		const query = `DELETE FROM todolist_management.todo_items WHERE id = $1`
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+	rolled, err := rollupCompletion(ctx, tx, pid)
+	if err != nil {
+		return err
+	}
+	if err := touchList(ctx, tx, lid); err != nil {
+		return err
+	}
+	events := append([]*Event{{Type: EventItemDeleted, ListID: lid, ItemID: id}}, rollupEvents(lid, rolled)...)
+	if err := c.record(ctx, tx, ids, events...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	c.publish(events...)
+	return nil
+}
+
+// touchList marks a list as updated for a change which left none of its
+// items updated, like the removal of one of them
+func touchList(ctx context.Context, tx *sql.Tx, lid int64) error {
+	const query = `UPDATE todolist_management.todo_lists SET updated_at = now() WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, lid); err != nil {
 		return err
 	}
 	return nil
 }
 
+// rollupCompletion completes an item when all of its children are completed
+// and reopens it otherwise, then does the same for its ancestors; it returns
+// the ids of the items it changed
+func rollupCompletion(ctx context.Context, tx *sql.Tx, pid int64) ([]int64, error) {
+	const query = `UPDATE todolist_management.todo_items AS p SET completed = c.done,
+		completed_at = CASE WHEN c.done THEN COALESCE(p.completed_at, now()) END, updated_at = now()
+		FROM (SELECT bool_and(completed IS TRUE) AS done FROM todolist_management.todo_items WHERE parent_id = $1) AS c
+		WHERE p.id = $1 AND c.done IS NOT NULL AND p.completed IS DISTINCT FROM c.done
+		returning COALESCE(p.parent_id, 0)`
+	ids := []int64{}
+	for pid != 0 {
+		id := pid
+		if err := tx.QueryRowContext(ctx, query, id).Scan(&pid); err != nil {
+			if err == sql.ErrNoRows {
+				return ids, nil
+			}
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 // GetTodoListItem returns a todolist item
-func (c *Core) GetTodoListItem(id int64) (*TodoItem, error) {
-	const query = `SELECT id, value, completed FROM todolist_management.todo_items WHERE id = $1`
+func (c *Core) GetTodoListItem(ctx context.Context, id int64) (_ *TodoItem, err error) {
+	ctx, end := startOperation(ctx, "GetTodoListItem")
+	defer end(&err)
+	v, err := cache.Load(c.cache, "item:"+strconv.FormatInt(id, 10), func() (interface{}, error) {
+		return c.getTodoListItem(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*TodoItem).clone(), nil
+}
+
+// getTodoListItem reads an item from the database
+func (c *Core) getTodoListItem(ctx context.Context, id int64) (*TodoItem, error) {
+	const query = `SELECT ` + itemColumns + ` FROM todolist_management.todo_items WHERE id = $1`
 	item := &TodoItem{}
-	if err := c.db.QueryRow(query, id).Scan(&item.ID, &item.Value, &item.Completed); err != nil {
+	if err := scanItem(c.db.QueryRowContext(ctx, query, id), item); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrItemNotFound
 		}
@@ -170,35 +637,514 @@ func (c *Core) GetTodoListItem(id int64) (*TodoItem, error) {
 	return item, nil
 }
 
-// UpdateTodoItem updates an item
-func (c *Core) UpdateTodoItem(item *TodoItem) error {
-	const query = `UPDATE todolist_management.todo_items SET value = $1, completed = $2 WHERE id = $3`
-	if _, err := c.db.Exec(query, item.Value, item.Completed, item.ID); err != nil {
+// UpdateTodoItem updates the value, completed, due_at, priority and
+// recurrence of an item, only the ones named in fields by their json names
+// when any is; completed_at is kept in step with completed and a changed due
+// date makes the item eligible for a new reminder. Completing a recurring item
+// adds its next occurrence at the end of its list. A parent_id other than the
+// one of the item is refused with ErrParent, items being moved under others
+// by MoveTodoItem.
+func (c *Core) UpdateTodoItem(ctx context.Context, item *TodoItem, fields ...string) (err error) {
+	ctx, end := startOperation(ctx, "UpdateTodoItem")
+	defer end(&err)
+	defer c.invalidate()
+	if err := item.valid(); err != nil {
 		return err
 	}
+	now := c.clock.Now()
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	const check = `SELECT ` + itemColumns + ` FROM todolist_management.todo_items WHERE id = $1 FOR UPDATE`
+	prev := &TodoItem{}
+	if err := scanItem(tx.QueryRowContext(ctx, check, item.ID), prev); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrItemNotFound
+		}
+		return err
+	}
+	if item.ParentID != 0 && item.ParentID != prev.ParentID {
+		return ErrParent
+	}
+	if err := checkList(ctx, tx, prev.ListID); err != nil {
+		return err
+	}
+	item.merge(prev, fields)
+
+	const query = `UPDATE todolist_management.todo_items SET value = $1, completed = $2, due_at = $4, priority = $5, recurrence = $6,
+		completed_at = CASE WHEN $2 THEN COALESCE(completed_at, $7) END,
+		reminded_at = CASE WHEN due_at IS NOT DISTINCT FROM $4 THEN reminded_at END,
+		updated_at = now()
+		WHERE id = $3 returning ` + itemColumns
+	updated := &TodoItem{}
+	row := tx.QueryRowContext(ctx, query, item.Value, item.Completed, item.ID, item.DueAt, item.Priority, item.Recurrence, now)
+	if err := scanItem(row, updated); err != nil {
+		return err
+	}
+	typ := EventItemUpdated
+	if !prev.Completed && item.Completed {
+		typ = EventItemCompleted
+	}
+	events := []*Event{itemEvent(typ, updated)}
+
+	if !prev.Completed && item.Completed {
+		// the occurrence follows the rule the completed item was scheduled by
+		next, err := c.addNextOccurrence(ctx, tx, prev, now)
+		if err != nil {
+			return err
+		}
+		if next != nil {
+			events = append(events, itemEvent(EventItemAdded, next))
+		}
+	}
+	if prev.Completed != item.Completed {
+		rolled, err := rollupCompletion(ctx, tx, prev.ParentID)
+		if err != nil {
+			return err
+		}
+		events = append(events, rollupEvents(prev.ListID, rolled)...)
+	}
+	if err := c.record(ctx, tx, nil, events...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	c.publish(events...)
+	return nil
+}
+
+// addNextOccurrence adds the occurrence following a just completed item, if
+// it recurs and its series has not ended, and returns it
+func (c *Core) addNextOccurrence(ctx context.Context, tx *sql.Tx, item *TodoItem, now time.Time) (*TodoItem, error) {
+	rule, err := ParseRecurrence(item.Recurrence)
+	if err != nil || rule == nil {
+		return nil, err
+	}
+	due, n, ok := nextOccurrence(rule, item, now)
+	if !ok {
+		return nil, nil
+	}
+
+	const query = `INSERT INTO todolist_management.todo_items (value, list_id, completed, position, due_at, priority, recurrence, series_id, occurrence, parent_id)
+		SELECT $1, $2, false, COALESCE(MAX(position), 0) + $3, $4, $5, $6, $7, $8, NULLIF($9, 0) FROM todolist_management.todo_items WHERE list_id = $2
+		ON CONFLICT (series_id, occurrence) DO NOTHING
+		returning ` + itemColumns
+	next := &TodoItem{}
+	row := tx.QueryRowContext(ctx, query, item.Value, item.ListID, positionGap, due, item.Priority, item.Recurrence, item.SeriesID, n, item.ParentID)
+	if err := scanItem(row, next); err != nil {
+		if err == sql.ErrNoRows {
+			// the occurrence was added when the item was completed before
+			return nil, nil
+		}
+		return nil, err
+	}
+	return next, nil
+}
+
+// UpdateTodoSeries updates the value, priority and recurrence of the
+// incomplete occurrences of a recurring item series, none of them being
+// updated when one is in an archived list
+func (c *Core) UpdateTodoSeries(ctx context.Context, sid int64, item *TodoItem) (err error) {
+	ctx, end := startOperation(ctx, "UpdateTodoSeries")
+	defer end(&err)
+	defer c.invalidate()
+	if err := item.valid(); err != nil {
+		return err
+	}
+
+	const check = `SELECT id FROM todolist_management.todo_items WHERE id = $1 OR series_id = $1 LIMIT 1`
+	cid := int64(0)
+	if err := c.db.QueryRowContext(ctx, check, sid).Scan(&cid); err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		return ErrItemNotFound
+	}
+
+	const query = `UPDATE todolist_management.todo_items SET value = $2, priority = $3, recurrence = $4, updated_at = now()
+		WHERE COALESCE(series_id, id) = $1 AND completed IS NOT TRUE returning ` + itemColumns
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := checkSeries(ctx, tx, sid); err != nil {
+		return err
+	}
+	rows, err := tx.QueryContext(ctx, query, sid, item.Value, item.Priority, item.Recurrence)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	events := []*Event{}
+	for rows.Next() {
+		item := &TodoItem{}
+		if err := scanItem(rows, item); err != nil {
+			return err
+		}
+		events = append(events, itemEvent(EventItemUpdated, item))
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if err := c.record(ctx, tx, nil, events...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	c.publish(events...)
 	return nil
 }
 
-// GetTodoList returns whole todolist
-func (c *Core) GetTodoList(id int64) (*TodoList, error) {
-	const query = `SELECT * FROM todolist_management.todo_lists INNER JOIN todolist_management.todo_items ON todolist_management.todo_items.list_id = todolist_management.todo_lists.id AND todolist_management.todo_lists.id = $1`
-	rows, err := c.db.Query(query, id)
+// GetTodoList returns whole todolist, sub-items being nested in the
+// Children of their parent; with a filter only the matching items are
+// returned, at the top level when their parent doesn't match
+func (c *Core) GetTodoList(ctx context.Context, id int64, filter *TagFilter) (_ *TodoList, err error) {
+	ctx, end := startOperation(ctx, "GetTodoList")
+	defer end(&err)
+	if filter != nil {
+		return c.getTodoList(ctx, id, filter)
+	}
+	v, err := cache.Load(c.cache, "list:"+strconv.FormatInt(id, 10), func() (interface{}, error) {
+		return c.getTodoList(ctx, id, nil)
+	})
 	if err != nil {
+		return nil, err
+	}
+	return v.(*TodoList).clone(), nil
+}
+
+// getTodoList reads a list and its items, those matching filter if given,
+// from the database
+func (c *Core) getTodoList(ctx context.Context, id int64, filter *TagFilter) (*TodoList, error) {
+	if filter != nil {
+		tags, err := normalizeTags(filter.Tags)
+		if err != nil {
+			return nil, err
+		}
+		filter = &TagFilter{Tags: tags, Any: filter.Any}
+	}
+
+	const listQuery = `SELECT ` + listColumns + ` FROM ` + listFrom + ` WHERE l.id = $1 GROUP BY l.id`
+	list := &TodoList{}
+	if err := scanList(c.db.QueryRowContext(ctx, listQuery, id), &list.ID, &list.Name, &list.ListStats); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrNotFound
 		}
 		return nil, err
 	}
+
+	const itemQuery = `SELECT ` + itemColumns + ` FROM todolist_management.todo_items
+		WHERE list_id = $3 AND ` + tagMatch + ` ORDER BY position, id`
+	names, n := filter.args()
+	items, err := c.queryItems(ctx, itemQuery, names, n, id)
+	if err != nil {
+		return nil, err
+	}
+	list.Items = buildItemTree(items)
+	return list, nil
+}
+
+// buildItemTree nests items under their parents, keeping their order; an item
+// whose parent is not in items is returned at the top level
+func buildItemTree(items []*TodoItem) []*TodoItem {
+	byID := make(map[int64]*TodoItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	roots := []*TodoItem{}
+	for _, item := range items {
+		if parent, ok := byID[item.ParentID]; ok {
+			parent.Children = append(parent.Children, item)
+			continue
+		}
+		roots = append(roots, item)
+	}
+	return roots
+}
+
+// ItemMove is the request to reposition an item; Before and After are
+// item ids in the target list, ListID, when set, moves it to another list
+// and ParentID, when set, nests it under another item of the target list or
+// at the top level for 0. Moving to another list without a ParentID moves the
+// item to the top level.
+type ItemMove struct {
+	ListID   int64  `json:"list_id"`
+	Before   int64  `json:"before"`
+	After    int64  `json:"after"`
+	ParentID *int64 `json:"parent_id"`
+}
+
+// MoveTodoItem places an item between its anchors, at the end of the target
+// list when no anchor is given; the children of the item move along with it
+func (c *Core) MoveTodoItem(ctx context.Context, id int64, move *ItemMove) (_ *TodoItem, err error) {
+	ctx, end := startOperation(ctx, "MoveTodoItem")
+	defer end(&err)
+	defer c.invalidate()
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	const itemQuery = `SELECT list_id, COALESCE(parent_id, 0) FROM todolist_management.todo_items WHERE id = $1 FOR UPDATE`
+	lid, pid := int64(0), int64(0)
+	if err := tx.QueryRowContext(ctx, itemQuery, id).Scan(&lid, &pid); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrItemNotFound
+		}
+		return nil, err
+	}
+	if err := checkList(ctx, tx, lid); err != nil {
+		return nil, err
+	}
+
+	from, parent := lid, pid
+	if move.ListID != 0 && move.ListID != lid {
+		if err := checkList(ctx, tx, move.ListID); err != nil {
+			return nil, err
+		}
+		lid, parent = move.ListID, 0
+	}
+	if move.ParentID != nil {
+		parent = *move.ParentID
+	}
+	if parent != 0 && (parent != pid || lid != from) {
+		// the new parent can't be the item itself or one of its descendants,
+		// and is in the list the item is moved to
+		const parentQuery = `WITH RECURSIVE tree AS (
+				SELECT id FROM todolist_management.todo_items WHERE id = $1
+				UNION SELECT i.id FROM todolist_management.todo_items i JOIN tree ON i.parent_id = tree.id
+			)
+			SELECT id FROM todolist_management.todo_items WHERE id = $2 AND list_id = $3 AND id NOT IN (SELECT id FROM tree)`
+		cid := int64(0)
+		if err := tx.QueryRowContext(ctx, parentQuery, id, parent, lid).Scan(&cid); err != nil {
+			if err != sql.ErrNoRows {
+				return nil, err
+			}
+			return nil, ErrParent
+		}
+	}
+
+	pos, err := movePosition(ctx, tx, id, lid, move)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	if pos == nil {
+		// anchors are adjacent, spread the list out and try once more
+		if ids, err = rebalanceList(ctx, tx, id, lid); err != nil {
+			return nil, err
+		}
+		if pos, err = movePosition(ctx, tx, id, lid, move); err != nil {
+			return nil, err
+		}
+		if pos == nil {
+			return nil, ErrInvalidMove
+		}
+	}
+
+	const query = `UPDATE todolist_management.todo_items SET list_id = $2, position = $3, parent_id = NULLIF($4, 0), updated_at = now()
+		WHERE id = $1 returning ` + itemColumns
+	item := &TodoItem{}
+	if err := scanItem(tx.QueryRowContext(ctx, query, id, lid, *pos, parent), item); err != nil {
+		return nil, err
+	}
+	if lid != from {
+		const childQuery = `WITH RECURSIVE tree AS (
+				SELECT id FROM todolist_management.todo_items WHERE parent_id = $1
+				UNION SELECT i.id FROM todolist_management.todo_items i JOIN tree ON i.parent_id = tree.id
+			)
+			UPDATE todolist_management.todo_items SET list_id = $2, updated_at = now() WHERE id IN (SELECT id FROM tree) RETURNING id`
+		children, err := queryIDs(ctx, tx, childQuery, id, lid)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, children...)
+		if err := touchList(ctx, tx, from); err != nil {
+			return nil, err
+		}
+	}
+	events := []*Event{itemEvent(EventItemUpdated, item)}
+	if lid != from {
+		// for the subscribers of each of the lists
+		events = []*Event{{Type: EventItemDeleted, ListID: from, ItemID: id}, itemEvent(EventItemAdded, item)}
+	}
+	if parent != pid {
+		rolled, err := rollupCompletion(ctx, tx, pid)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, rollupEvents(from, rolled)...)
+		if rolled, err = rollupCompletion(ctx, tx, parent); err != nil {
+			return nil, err
+		}
+		events = append(events, rollupEvents(lid, rolled)...)
+	}
+	if err := c.record(ctx, tx, ids, events...); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	c.publish(events...)
+	return item, nil
+}
+
+// movePosition computes the new position of item id in list lid, it returns
+// nil when there is no free position left between the anchors
+func movePosition(ctx context.Context, tx *sql.Tx, id, lid int64, move *ItemMove) (*int64, error) {
+	const anchorQuery = `SELECT position FROM todolist_management.todo_items WHERE id = $1 AND list_id = $2 AND id <> $3`
+	const nextQuery = `SELECT MIN(position) FROM todolist_management.todo_items WHERE list_id = $1 AND position > $2 AND id <> $3`
+	const prevQuery = `SELECT MAX(position) FROM todolist_management.todo_items WHERE list_id = $1 AND position < $2 AND id <> $3`
+	const lastQuery = `SELECT MAX(position) FROM todolist_management.todo_items WHERE list_id = $1 AND id <> $2`
+
+	anchor := func(aid int64) (int64, error) {
+		pos := int64(0)
+		if err := tx.QueryRowContext(ctx, anchorQuery, aid, lid, id).Scan(&pos); err != nil {
+			if err == sql.ErrNoRows {
+				return 0, ErrInvalidMove
+			}
+			return 0, err
+		}
+		return pos, nil
+	}
+
+	var lower, upper sql.NullInt64
+	switch {
+	case move.After != 0 && move.Before != 0:
+		after, err := anchor(move.After)
+		if err != nil {
+			return nil, err
+		}
+		before, err := anchor(move.Before)
+		if err != nil {
+			return nil, err
+		}
+		if after >= before {
+			return nil, ErrInvalidMove
+		}
+		lower = sql.NullInt64{Int64: after, Valid: true}
+		upper = sql.NullInt64{Int64: before, Valid: true}
+	case move.After != 0:
+		after, err := anchor(move.After)
+		if err != nil {
+			return nil, err
+		}
+		lower = sql.NullInt64{Int64: after, Valid: true}
+		if err := tx.QueryRowContext(ctx, nextQuery, lid, after, id).Scan(&upper); err != nil {
+			return nil, err
+		}
+	case move.Before != 0:
+		before, err := anchor(move.Before)
+		if err != nil {
+			return nil, err
+		}
+		upper = sql.NullInt64{Int64: before, Valid: true}
+		if err := tx.QueryRowContext(ctx, prevQuery, lid, before, id).Scan(&lower); err != nil {
+			return nil, err
+		}
+	default:
+		if err := tx.QueryRowContext(ctx, lastQuery, lid, id).Scan(&lower); err != nil {
+			return nil, err
+		}
+	}
+
+	pos := int64(0)
+	switch {
+	case lower.Valid && upper.Valid:
+		if upper.Int64-lower.Int64 < 2 {
+			return nil, nil
+		}
+		pos = lower.Int64 + (upper.Int64-lower.Int64)/2
+	case lower.Valid:
+		pos = lower.Int64 + positionGap
+	case upper.Valid:
+		pos = upper.Int64 - positionGap
+	default:
+		pos = positionGap
+	}
+	return &pos, nil
+}
+
+// rebalanceList renumbers the items of a list, except the one being moved,
+// with positionGap between each of them; it returns the ids of the items it
+// changed
+func rebalanceList(ctx context.Context, tx *sql.Tx, id, lid int64) ([]int64, error) {
+	const query = `UPDATE todolist_management.todo_items SET position = ranked.rank * $3
+		FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY position, id) AS rank FROM todolist_management.todo_items WHERE list_id = $1 AND id <> $2) AS ranked
+		WHERE todolist_management.todo_items.id = ranked.id AND position <> ranked.rank * $3
+		RETURNING todolist_management.todo_items.id`
+	return queryIDs(ctx, tx, query, lid, id, positionGap)
+}
+
+// queryIDs runs a statement returning the ids of the rows it changed
+func queryIDs(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
-	list := &TodoList{
-		Items: []*TodoItem{},
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetOverdueItems returns the incomplete items of all the lists which were due
+// before now, earliest first; the ones of archived lists only if asked to
+// and never the ones of templates
+func (c *Core) GetOverdueItems(ctx context.Context, now time.Time, archived bool) (_ []*TodoItem, err error) {
+	ctx, end := startOperation(ctx, "GetOverdueItems")
+	defer end(&err)
+	const query = `SELECT ` + itemColumns + ` FROM todolist_management.todo_items
+		WHERE completed IS NOT TRUE AND due_at < $1
+		AND list_id IN (SELECT id FROM todolist_management.todo_lists WHERE NOT template AND ($2 OR NOT archived)) ORDER BY due_at, id`
+	return c.queryItems(ctx, query, now, archived)
+}
+
+// GetItemsDueBetween returns the items of all the lists due in [from, to),
+// earliest first; the ones of archived lists only if asked to and never the
+// ones of templates
+func (c *Core) GetItemsDueBetween(ctx context.Context, from, to time.Time, archived bool) (_ []*TodoItem, err error) {
+	ctx, end := startOperation(ctx, "GetItemsDueBetween")
+	defer end(&err)
+	const query = `SELECT ` + itemColumns + ` FROM todolist_management.todo_items
+		WHERE due_at >= $1 AND due_at < $2
+		AND list_id IN (SELECT id FROM todolist_management.todo_lists WHERE NOT template AND ($3 OR NOT archived)) ORDER BY due_at, id`
+	return c.queryItems(ctx, query, from, to, archived)
+}
+
+// queryItems runs a query selecting itemColumns and returns its items
+func (c *Core) queryItems(ctx context.Context, query string, args ...interface{}) ([]*TodoItem, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
+	items := []*TodoItem{}
 	for rows.Next() {
 		item := &TodoItem{}
-		if err = rows.Scan(&list.ID, &list.Name, &item.ID, &item.Value, &list.ID, &item.Completed); err != nil {
+		if err := scanItem(rows, item); err != nil {
 			return nil, err
 		}
-		list.Items = append(list.Items, item)
+		items = append(items, item)
 	}
-	return list, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
@@ -0,0 +1,202 @@
+package todolist
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/cache"
+	"github.com/Shivam010/go-rest-api/dbtest"
+)
+
+var created = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// listRow returns the listColumns of a list
+func listRow(id int64, name string, items, completed int64) []driver.Value {
+	return []driver.Value{id, name, false, false, items, completed, created, created, nil}
+}
+
+// itemRow returns the itemColumns of an item
+func itemRow(id, lid, parent int64, value string, completed bool) []driver.Value {
+	return []driver.Value{id, lid, value, completed, id * positionGap, nil, "normal", nil, created, created, "", id, int64(1), parent, []byte("{}")}
+}
+
+// listsDB answers the list and item queries of GetTodoList with lists and
+// items, by list id
+func listsDB(lists map[int64][]driver.Value, items map[int64][][]driver.Value) dbtest.Answer {
+	return func(query string, args []driver.Value) ([][]driver.Value, error) {
+		switch {
+		case strings.Contains(query, "WHERE l.id = $1"):
+			if list, ok := lists[args[0].(int64)]; ok {
+				return [][]driver.Value{list}, nil
+			}
+			return nil, nil
+		case strings.Contains(query, "WHERE list_id = $3"):
+			return items[args[2].(int64)], nil
+		}
+		return nil, nil
+	}
+}
+
+func TestGetTodoList(t *testing.T) {
+	db := dbtest.Open(listsDB(
+		map[int64][]driver.Value{
+			1: listRow(1, "empty", 0, 0),
+			2: listRow(2, "groceries", 3, 1),
+		},
+		map[int64][][]driver.Value{
+			2: {itemRow(10, 2, 0, "milk", true), itemRow(11, 2, 0, "fruits", false), itemRow(12, 2, 11, "apples", false)},
+		},
+	))
+	c := NewCore(db)
+
+	tests := []struct {
+		name    string
+		id      int64
+		wantErr error
+		want    string
+	}{
+		{"missing list", 3, ErrNotFound, ""},
+		{"empty list", 1, nil, `"items":[]`},
+		{"populated list", 2, nil, `"items":[{"id":10,`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := c.GetTodoList(context.Background(), tt.id, nil)
+			if err != tt.wantErr {
+				t.Fatalf("GetTodoList(%d) error = %v, want %v", tt.id, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if list.ID != tt.id {
+				t.Errorf("GetTodoList(%d) id = %d", tt.id, list.ID)
+			}
+			b, _ := json.Marshal(list)
+			if !strings.Contains(string(b), tt.want) {
+				t.Errorf("GetTodoList(%d) = %s, want %s in it", tt.id, b, tt.want)
+			}
+		})
+	}
+
+	list, err := c.GetTodoList(context.Background(), 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.ItemCount != 3 || list.CompletedCount != 1 || !list.CreatedAt.Equal(created) {
+		t.Errorf("stats = %+v, want 3 items, 1 completed, created at %v", list.ListStats, created)
+	}
+	if len(list.Items) != 2 || len(list.Items[1].Children) != 1 || list.Items[1].Children[0].ID != 12 {
+		t.Errorf("items = %+v, want 2 items, the second with the child 12", list.Items)
+	}
+}
+
+func TestRecordWritesChanges(t *testing.T) {
+	var statements []string
+	var lists, items driver.Value
+	db := dbtest.Open(func(query string, args []driver.Value) ([][]driver.Value, error) {
+		statements = append(statements, query)
+		if strings.Contains(query, "INSERT INTO changes.events") {
+			lists, items = args[0], args[1]
+		}
+		return nil, nil
+	})
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := []*Event{
+		{Type: EventItemDeleted, ListID: 1, ItemID: 10},
+		{Type: EventItemAdded, ListID: 2, ItemID: 10},
+		{Type: EventItemUpdated, ListID: 1, ItemID: 11},
+	}
+	// the child of the deleted item, moved up to its parent
+	if err := NewCore(db).record(context.Background(), tx, []int64{12}, events...); err != nil {
+		t.Fatal(err)
+	}
+	if last := statements[len(statements)-1]; !strings.Contains(last, "INSERT INTO changes.events") {
+		t.Errorf("last statement = %q, want the change log written last", last)
+	}
+	if lists != "{1,2}" || items != "{12,10,11}" {
+		t.Errorf("changed lists, items = %v, %v, want {1,2}, {12,10,11}", lists, items)
+	}
+}
+
+func TestUpdateTodoItemParent(t *testing.T) {
+	updates := 0
+	db := dbtest.Open(func(query string, args []driver.Value) ([][]driver.Value, error) {
+		switch {
+		case strings.Contains(query, "FROM todolist_management.todo_items WHERE id = $1 FOR UPDATE"):
+			return [][]driver.Value{itemRow(12, 2, 11, "apples", false)}, nil
+		case strings.Contains(query, "UPDATE todolist_management.todo_items SET value"):
+			updates++
+			return [][]driver.Value{itemRow(12, 2, 11, args[0].(string), false)}, nil
+		case strings.Contains(query, "SELECT archived"):
+			return [][]driver.Value{{false}}, nil
+		}
+		return nil, nil
+	})
+	c := NewCore(db)
+
+	tests := []struct {
+		name    string
+		parent  int64
+		wantErr error
+		updates int
+	}{
+		{"omitted", 0, nil, 1},
+		{"unchanged", 11, nil, 1},
+		{"moved", 10, ErrParent, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates = 0
+			item := &TodoItem{ID: 12, Value: "pears", Priority: "normal", ParentID: tt.parent}
+			err := c.UpdateTodoItem(context.Background(), item, "value", "parent_id")
+			if err != tt.wantErr {
+				t.Errorf("UpdateTodoItem(parent %d) error = %v, want %v", tt.parent, err, tt.wantErr)
+			}
+			if updates != tt.updates {
+				t.Errorf("UpdateTodoItem(parent %d) updated %d times, want %d", tt.parent, updates, tt.updates)
+			}
+		})
+	}
+}
+
+func TestGetTodoListCopiesCachedList(t *testing.T) {
+	reads := 0
+	db := dbtest.Open(func(query string, args []driver.Value) ([][]driver.Value, error) {
+		switch {
+		case strings.Contains(query, "WHERE l.id = $1"):
+			reads++
+			return [][]driver.Value{listRow(2, "groceries", 1, 0)}, nil
+		case strings.Contains(query, "WHERE list_id = $3"):
+			return [][]driver.Value{itemRow(10, 2, 0, "milk", false)}, nil
+		}
+		return nil, nil
+	})
+	c := NewCore(db)
+	c.SetCache(cache.NewLRU("test", 10, time.Minute))
+
+	list, err := c.GetTodoList(context.Background(), 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list.Name = "changed"
+	list.Items[0].Value = "changed"
+	list.Items[0].Tags = append(list.Items[0].Tags, "changed")
+
+	list, err = c.GetTodoList(context.Background(), 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reads != 1 {
+		t.Errorf("list read %d times, want it cached", reads)
+	}
+	if list.Name != "groceries" || list.Items[0].Value != "milk" || len(list.Items[0].Tags) != 0 {
+		t.Errorf("cached list = %+v, %+v, want it unchanged by its readers", list, list.Items[0])
+	}
+}
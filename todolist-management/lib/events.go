@@ -0,0 +1,211 @@
+package todolist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/changes"
+	"github.com/Shivam010/go-rest-api/webhooks"
+)
+
+// ErrEventsLost is returned when the events following the last one seen by
+// a client can't be replayed, it has to read the list again
+var ErrEventsLost = errors.New("events lost")
+
+// Event types
+const (
+	EventItemAdded      = "item.added"
+	EventItemUpdated    = "item.updated"
+	EventItemCompleted  = "item.completed"
+	EventItemDeleted    = "item.deleted"
+	EventListCreated    = "list.created"
+	EventListRenamed    = "list.renamed"
+	EventListArchived   = "list.archived"
+	EventListUnarchived = "list.unarchived"
+	EventListDeleted    = "list.deleted"
+)
+
+// Event is a change made to a list, Item is only set for the changes of an
+// item made directly, not for the ones following from it like the
+// completion of its parent
+type Event struct {
+	ID     string    `json:"id,omitempty"`
+	Type   string    `json:"type"`
+	ListID int64     `json:"list_id"`
+	ItemID int64     `json:"item_id,omitempty"`
+	Item   *TodoItem `json:"item,omitempty"`
+	Name   string    `json:"name,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// itemEvent returns the event of a change of item
+func itemEvent(typ string, item *TodoItem) *Event {
+	return &Event{Type: typ, ListID: item.ListID, ItemID: item.ID, Item: item}
+}
+
+// Subscription receives the events of a list on C, which is closed when the
+// subscriber falls too far behind; it must be closed once done with
+type Subscription struct {
+	C    <-chan *Event
+	c    chan *Event
+	list int64
+	b    *Broker
+}
+
+// Close stops the subscription
+func (s *Subscription) Close() {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if _, ok := s.b.subs[s.list][s]; ok {
+		delete(s.b.subs[s.list], s)
+		close(s.c)
+	}
+	if len(s.b.subs[s.list]) == 0 {
+		delete(s.b.subs, s.list)
+	}
+}
+
+// Broker is the in-process pub/sub of the events of the lists, it keeps
+// the last events to replay them to the clients reconnecting. Event IDs are
+// the start time of the broker followed by a sequence number, those of a
+// previous broker are not replayed from
+type Broker struct {
+	mu      sync.Mutex
+	epoch   string
+	seq     uint64
+	history []*Event
+	size    int
+	subs    map[int64]map[*Subscription]struct{}
+}
+
+// NewBroker returns a broker keeping the last size events
+func NewBroker(size int) *Broker {
+	return &Broker{
+		epoch: strconv.FormatInt(time.Now().UnixNano(), 36),
+		size:  size,
+		subs:  map[int64]map[*Subscription]struct{}{},
+	}
+}
+
+// Publish numbers the events and sends them to the subscribers of their list
+func (b *Broker) Publish(events ...*Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	for _, e := range events {
+		b.seq++
+		e.ID = b.epoch + "-" + strconv.FormatUint(b.seq, 10)
+		if e.At.IsZero() {
+			e.At = now
+		}
+		if b.history = append(b.history, e); len(b.history) > b.size {
+			b.history = b.history[len(b.history)-b.size:]
+		}
+		for s := range b.subs[e.ListID] {
+			select {
+			case s.c <- e:
+			default:
+				// too far behind, the subscriber reconnects and resumes
+				delete(b.subs[e.ListID], s)
+				close(s.c)
+			}
+		}
+	}
+}
+
+// Subscribe subscribes to the events of a list following lastID, the ones
+// already published being returned, with ErrEventsLost when some of them
+// are no longer kept
+func (b *Broker) Subscribe(list int64, lastID string) (*Subscription, []*Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := make(chan *Event, 64)
+	s := &Subscription{C: c, c: c, list: list, b: b}
+	if b.subs[list] == nil {
+		b.subs[list] = map[*Subscription]struct{}{}
+	}
+	b.subs[list][s] = struct{}{}
+
+	if lastID == "" {
+		return s, nil, nil
+	}
+	epoch, seq := "", uint64(0)
+	if i := strings.LastIndex(lastID, "-"); i > 0 {
+		epoch = lastID[:i]
+		seq, _ = strconv.ParseUint(lastID[i+1:], 10, 64)
+	}
+	if epoch != b.epoch || seq > b.seq {
+		return s, nil, ErrEventsLost
+	}
+	if seq == b.seq {
+		return s, nil, nil
+	}
+	// the history holds the events numbered from oldest to b.seq
+	oldest := b.seq - uint64(len(b.history)) + 1
+	if seq+1 < oldest {
+		return s, nil, ErrEventsLost
+	}
+	replay := []*Event{}
+	for _, e := range b.history[seq+1-oldest:] {
+		if e.ListID == list {
+			replay = append(replay, e)
+		}
+	}
+	return s, replay, nil
+}
+
+// SubscribeTodoList subscribes to the events of a list, see Broker.Subscribe
+func (c *Core) SubscribeTodoList(ctx context.Context, id int64, lastID string) (*Subscription, []*Event, error) {
+	const check = `SELECT id FROM todolist_management.todo_lists WHERE id = $1`
+	cid := int64(0)
+	if err := c.db.QueryRowContext(ctx, check, id).Scan(&cid); err != nil {
+		if err != sql.ErrNoRows {
+			return nil, nil, err
+		}
+		return nil, nil, ErrNotFound
+	}
+	return c.events.Subscribe(id, lastID)
+}
+
+// record writes the events of a change to the webhooks outbox, and the
+// changes of their lists and items to the change log, in the transaction of
+// the change, right before it commits; items are the other items the change
+// updated or removed, like the children of a removed item
+func (c *Core) record(ctx context.Context, tx *sql.Tx, items []int64, events ...*Event) error {
+	now := c.clock.Now()
+	scope := changes.Scope{Items: slices.Clone(items)}
+	for _, e := range events {
+		e.At = now
+		if err := webhooks.Enqueue(ctx, tx, e.Type, e); err != nil {
+			return err
+		}
+		if !slices.Contains(scope.Lists, e.ListID) {
+			scope.Lists = append(scope.Lists, e.ListID)
+		}
+		if e.ItemID != 0 && !slices.Contains(scope.Items, e.ItemID) {
+			scope.Items = append(scope.Items, e.ItemID)
+		}
+	}
+	return changes.Write(ctx, tx, scope)
+}
+
+// publish publishes the events of a change once it is committed
+func (c *Core) publish(events ...*Event) {
+	c.events.Publish(events...)
+}
+
+// rollupEvents returns the events of the items completed or reopened by a
+// rollup of their children
+func rollupEvents(lid int64, ids []int64) []*Event {
+	events := make([]*Event, 0, len(ids))
+	for _, id := range ids {
+		events = append(events, &Event{Type: EventItemUpdated, ListID: lid, ItemID: id})
+	}
+	return events
+}
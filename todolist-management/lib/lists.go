@@ -0,0 +1,219 @@
+package todolist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// ErrSort is returned for a list sort order which is not supported
+var ErrSort = errors.New("invalid sort order")
+
+// TodoListSummary is a list without its items
+type TodoListSummary struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	ListStats
+}
+
+// ListsPage is a page of the lists, Total being the number of all of them
+type ListsPage struct {
+	Lists  []*TodoListSummary `json:"lists"`
+	Total  int                `json:"total"`
+	Limit  int                `json:"limit"`
+	Offset int                `json:"offset"`
+}
+
+// ListOptions paginates and sorts the lists, by one of the keys of listSorts,
+// archived lists and templates being left out unless Archived or Templates
+// is set
+type ListOptions struct {
+	Limit     int
+	Offset    int
+	Sort      string
+	Desc      bool
+	Archived  bool
+	Templates bool
+}
+
+// listSorts maps the sort keys of ListOptions to their listColumns expression
+var listSorts = map[string]string{
+	"":           "l.id",
+	"id":         "l.id",
+	"name":       "l.name",
+	"item_count": "COUNT(i.id)",
+	"completion": "COALESCE(COUNT(i.id) FILTER (WHERE i.completed)::float / NULLIF(COUNT(i.id), 0), 0)",
+	"created_at": "l.created_at",
+	"updated_at": "GREATEST(l.updated_at, MAX(i.updated_at))",
+}
+
+// ListTodoLists returns a page of the lists with their stats, without
+// loading their items
+func (c *Core) ListTodoLists(ctx context.Context, opts *ListOptions) (_ *ListsPage, err error) {
+	ctx, end := startOperation(ctx, "ListTodoLists")
+	defer end(&err)
+	order, ok := listSorts[opts.Sort]
+	if !ok {
+		return nil, ErrSort
+	}
+	if opts.Desc {
+		order += " DESC"
+	}
+
+	page := &ListsPage{Lists: []*TodoListSummary{}, Limit: opts.Limit, Offset: opts.Offset}
+	const count = `SELECT COUNT(*) FROM todolist_management.todo_lists WHERE ($1 OR NOT archived) AND ($2 OR NOT template)`
+	if err := c.db.QueryRowContext(ctx, count, opts.Archived, opts.Templates).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + listColumns + ` FROM ` + listFrom + ` WHERE ($3 OR NOT l.archived) AND ($4 OR NOT l.template)
+		GROUP BY l.id ORDER BY ` + order + `, l.id LIMIT $1 OFFSET $2`
+	rows, err := c.db.QueryContext(ctx, query, opts.Limit, opts.Offset, opts.Archived, opts.Templates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		list := &TodoListSummary{}
+		if err := scanList(rows, &list.ID, &list.Name, &list.ListStats); err != nil {
+			return nil, err
+		}
+		page.Lists = append(page.Lists, list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// GetTodoListSummaries returns the lists of ids with their stats, by id,
+// without loading their items; the ids of no list are left out
+func (c *Core) GetTodoListSummaries(ctx context.Context, ids []int64) (_ map[int64]*TodoListSummary, err error) {
+	ctx, end := startOperation(ctx, "GetTodoListSummaries")
+	defer end(&err)
+	const query = `SELECT ` + listColumns + ` FROM ` + listFrom + ` WHERE l.id = ANY($1) GROUP BY l.id`
+	rows, err := c.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	lists := make(map[int64]*TodoListSummary, len(ids))
+	for rows.Next() {
+		list := &TodoListSummary{}
+		if err := scanList(rows, &list.ID, &list.Name, &list.ListStats); err != nil {
+			return nil, err
+		}
+		lists[list.ID] = list
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// GetOwnersTodoLists returns the lists of the users of ids, by user id and
+// sorted by id, archived lists and templates included, without loading their
+// items; the users without lists are left out
+func (c *Core) GetOwnersTodoLists(ctx context.Context, ids []int64) (_ map[int64][]*TodoListSummary, err error) {
+	ctx, end := startOperation(ctx, "GetOwnersTodoLists")
+	defer end(&err)
+	const query = `SELECT ` + listColumns + ` FROM ` + listFrom + ` WHERE l.owner_id = ANY($1) GROUP BY l.id ORDER BY l.id`
+	rows, err := c.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	byOwner := map[int64][]*TodoListSummary{}
+	for rows.Next() {
+		list := &TodoListSummary{}
+		if err := scanList(rows, &list.ID, &list.Name, &list.ListStats); err != nil {
+			return nil, err
+		}
+		byOwner[*list.OwnerID] = append(byOwner[*list.OwnerID], list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return byOwner, nil
+}
+
+// GetTodoListsItems returns the items of the lists of ids, by list id, nested
+// as by GetTodoList; the lists without items are left out
+func (c *Core) GetTodoListsItems(ctx context.Context, ids []int64) (_ map[int64][]*TodoItem, err error) {
+	ctx, end := startOperation(ctx, "GetTodoListsItems")
+	defer end(&err)
+	const query = `SELECT ` + itemColumns + ` FROM todolist_management.todo_items
+		WHERE list_id = ANY($1) ORDER BY list_id, position, id`
+	items, err := c.queryItems(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	byList := map[int64][]*TodoItem{}
+	for _, item := range items {
+		byList[item.ListID] = append(byList[item.ListID], item)
+	}
+	for id, items := range byList {
+		byList[id] = buildItemTree(items)
+	}
+	return byList, nil
+}
+
+// ArchiveTodoList archives a list, it is then left out of the listings and
+// searches and can't be written to until unarchived
+func (c *Core) ArchiveTodoList(ctx context.Context, id int64) (err error) {
+	ctx, end := startOperation(ctx, "ArchiveTodoList")
+	defer end(&err)
+	defer c.invalidate()
+	return c.setArchived(ctx, id, true)
+}
+
+// UnarchiveTodoList restores an archived list
+func (c *Core) UnarchiveTodoList(ctx context.Context, id int64) (err error) {
+	ctx, end := startOperation(ctx, "UnarchiveTodoList")
+	defer end(&err)
+	defer c.invalidate()
+	return c.setArchived(ctx, id, false)
+}
+
+func (c *Core) setArchived(ctx context.Context, id int64, archived bool) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	const query = `UPDATE todolist_management.todo_lists SET archived = $2, updated_at = now() WHERE id = $1 AND archived <> $2`
+	res, err := tx.ExecContext(ctx, query, id, archived)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n != 0 {
+		event := &Event{Type: EventListUnarchived, ListID: id}
+		if archived {
+			event.Type = EventListArchived
+		}
+		if err := c.record(ctx, tx, nil, event); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		c.publish(event)
+		return nil
+	}
+	// nothing changed, either the list is missing or already in that state
+	const check = `SELECT id FROM todolist_management.todo_lists WHERE id = $1`
+	cid := int64(0)
+	if err := tx.QueryRowContext(ctx, check, id).Scan(&cid); err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package todolist
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// metrics of the operations of the core
+var (
+	operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "todolist_core_operation_duration_seconds",
+		Help:    "Latency of the operations of the todolist core.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation"})
+	operationErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "todolist_core_operation_errors_total",
+		Help: "Number of the operations of the todolist core which failed.",
+	}, []string{"operation"})
+)
+
+// tracer of the operations of the core
+var tracer = otel.Tracer("github.com/Shivam010/go-rest-api/todolist-management/lib")
+
+// startOperation starts the span of an operation of the core, the returned
+// func ends it and records its latency, and its failure if *err is set, once
+// the operation returns
+func startOperation(ctx context.Context, op string) (context.Context, func(err *error)) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, "todolist."+op, trace.WithSpanKind(trace.SpanKindInternal))
+	return ctx, func(err *error) {
+		operationDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+		if *err != nil {
+			operationErrors.WithLabelValues(op).Inc()
+			span.RecordError(*err)
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+		span.End()
+	}
+}
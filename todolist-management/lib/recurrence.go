@@ -0,0 +1,228 @@
+package todolist
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRecurrence is returned for a recurrence rule which can't be parsed
+var ErrRecurrence = errors.New("invalid item recurrence")
+
+// Recurrence frequencies
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+)
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Recurrence is the subset of an RFC 5545 RRULE supported for todo items:
+// FREQ, INTERVAL, COUNT, UNTIL, BYDAY (weekly) and BYMONTHDAY (monthly),
+// e.g. "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH"
+type Recurrence struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+// ParseRecurrence parses an RRULE, an empty rule is no recurrence and
+// returns nil
+func ParseRecurrence(rule string) (*Recurrence, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return nil, nil
+	}
+	r := &Recurrence{Interval: 1}
+	for _, part := range strings.Split(rule, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, ErrRecurrence
+		}
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+		switch key {
+		case "FREQ":
+			switch value {
+			case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+				r.Freq = value
+			default:
+				return nil, ErrRecurrence
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, ErrRecurrence
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, ErrRecurrence
+			}
+			r.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, ErrRecurrence
+			}
+			r.Until = until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				wd, ok := weekdays[day]
+				if !ok {
+					return nil, ErrRecurrence
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, ErrRecurrence
+				}
+				r.ByMonthDay = append(r.ByMonthDay, n)
+			}
+		default:
+			return nil, ErrRecurrence
+		}
+	}
+	if r.Freq == "" || (r.Count != 0 && !r.Until.IsZero()) {
+		return nil, ErrRecurrence
+	}
+	if (len(r.ByDay) != 0 && r.Freq != FreqWeekly) || (len(r.ByMonthDay) != 0 && r.Freq != FreqMonthly) {
+		return nil, ErrRecurrence
+	}
+	return r, nil
+}
+
+// parseUntil accepts the RFC 5545 date and UTC date-time forms
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	return time.Parse("20060102", value)
+}
+
+// Next returns the first occurrence after t of the rule, given that t is its
+// n-th occurrence, and false when the series has ended
+func (r *Recurrence) Next(t time.Time, n int) (time.Time, bool) {
+	if r.Count != 0 && n >= r.Count {
+		return time.Time{}, false
+	}
+	var next time.Time
+	switch r.Freq {
+	case FreqDaily:
+		next = t.AddDate(0, 0, r.Interval)
+	case FreqWeekly:
+		next = r.nextWeekly(t)
+	case FreqMonthly:
+		next = r.nextMonthly(t)
+	case FreqYearly:
+		next = r.nextYearly(t)
+	}
+	if !r.Until.IsZero() && next.After(r.Until) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// nextWeekly returns the next day of ByDay in every Interval-th week, weeks
+// starting on Monday as per the RFC 5545 default
+func (r *Recurrence) nextWeekly(t time.Time) time.Time {
+	if len(r.ByDay) == 0 {
+		return t.AddDate(0, 0, 7*r.Interval)
+	}
+	week := startOfWeek(t)
+	for d := 1; d <= 7*(r.Interval+1); d++ {
+		next := t.AddDate(0, 0, d)
+		weeks := int(startOfWeek(next).Sub(week).Hours()+12) / (7 * 24)
+		if weeks%r.Interval != 0 {
+			continue
+		}
+		for _, wd := range r.ByDay {
+			if next.Weekday() == wd {
+				return next
+			}
+		}
+	}
+	return t.AddDate(0, 0, 7*r.Interval)
+}
+
+// nextMonthly returns the next day of ByMonthDay, or of the day of t, in
+// every Interval-th month; months without that day are skipped
+func (r *Recurrence) nextMonthly(t time.Time) time.Time {
+	days := r.ByMonthDay
+	if len(days) == 0 {
+		days = []int{t.Day()}
+	}
+	y, m, _ := t.Date()
+	hh, mm, ss := t.Clock()
+	for k := 0; k < 12*r.Interval*4; k += r.Interval {
+		first := time.Date(y, m+time.Month(k), 1, hh, mm, ss, t.Nanosecond(), t.Location())
+		last := first.AddDate(0, 1, -1).Day()
+		candidates := []int{}
+		for _, day := range days {
+			if day < 0 {
+				day = last + day + 1
+			}
+			if day >= 1 && day <= last {
+				candidates = append(candidates, day)
+			}
+		}
+		sort.Ints(candidates)
+		for _, day := range candidates {
+			if next := first.AddDate(0, 0, day-1); next.After(t) {
+				return next
+			}
+		}
+	}
+	return t.AddDate(0, r.Interval, 0)
+}
+
+// nextYearly returns the same day in the Interval-th next year, skipping the
+// years without a February 29th for items due on it
+func (r *Recurrence) nextYearly(t time.Time) time.Time {
+	for k := r.Interval; ; k += r.Interval {
+		if next := t.AddDate(k, 0, 0); next.Day() == t.Day() {
+			return next
+		}
+	}
+}
+
+// startOfWeek returns the midnight of the Monday of the week of t
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	y, m, d := t.AddDate(0, 0, -offset).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// nextOccurrence returns the due date and number of the occurrence following
+// item, skipping the occurrences which would already be past due at now
+func nextOccurrence(r *Recurrence, item *TodoItem, now time.Time) (time.Time, int, bool) {
+	due, n := now, item.Occurrence
+	if item.DueAt != nil {
+		due = *item.DueAt
+	}
+	for {
+		next, ok := r.Next(due, n)
+		if !ok || next.After(now) {
+			return next, n + 1, ok
+		}
+		due, n = next, n+1
+	}
+}
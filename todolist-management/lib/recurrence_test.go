@@ -0,0 +1,74 @@
+package todolist
+
+import (
+	"testing"
+	"time"
+)
+
+// fixedClock is a Clock stopped at a time
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 9, 30, 0, 0, time.UTC)
+}
+
+func TestNextOccurrence(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       string
+		due        time.Time
+		occurrence int
+		now        time.Time
+		want       time.Time
+		wantN      int
+		wantOK     bool
+	}{
+		{"daily into the next year", "FREQ=DAILY", date(2025, 12, 31), 1, date(2025, 12, 31), date(2026, 1, 1), 2, true},
+		{"weekly into the next year", "FREQ=WEEKLY;BYDAY=MO", date(2025, 12, 30), 1, date(2025, 12, 30), date(2026, 1, 5), 2, true},
+		{"monthly into the next year", "FREQ=MONTHLY", date(2025, 12, 15), 3, date(2025, 12, 15), date(2026, 1, 15), 4, true},
+		{"monthly skips the months without the day", "FREQ=MONTHLY", date(2025, 1, 31), 1, date(2025, 1, 31), date(2025, 3, 31), 2, true},
+		{"monthly on the last day", "FREQ=MONTHLY;BYMONTHDAY=-1", date(2025, 1, 31), 1, date(2025, 1, 31), date(2025, 2, 28), 2, true},
+		{"monthly on the last day of a leap year", "FREQ=MONTHLY;BYMONTHDAY=-1", date(2024, 1, 31), 1, date(2024, 1, 31), date(2024, 2, 29), 2, true},
+		{"monthly every other month into the next year", "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=1,15", date(2025, 11, 15), 1, date(2025, 11, 15), date(2026, 1, 1), 2, true},
+		{"yearly", "FREQ=YEARLY", date(2025, 3, 1), 1, date(2025, 3, 1), date(2026, 3, 1), 2, true},
+		{"yearly on a leap day", "FREQ=YEARLY", date(2024, 2, 29), 1, date(2024, 2, 29), date(2028, 2, 29), 2, true},
+		{"past due occurrences are skipped", "FREQ=MONTHLY", date(2025, 1, 31), 1, date(2025, 4, 15), date(2025, 5, 31), 3, true},
+		{"until the end of the year", "FREQ=MONTHLY;UNTIL=20251231", date(2025, 12, 15), 1, date(2025, 12, 15), time.Time{}, 2, false},
+		{"count reached", "FREQ=DAILY;COUNT=2", date(2025, 12, 31), 2, date(2025, 12, 31), time.Time{}, 3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRecurrence(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrence(%q): %v", tt.rule, err)
+			}
+			clock := fixedClock(tt.now)
+			due := tt.due
+			item := &TodoItem{DueAt: &due, Recurrence: tt.rule, Occurrence: tt.occurrence}
+			got, n, ok := nextOccurrence(rule, item, clock.Now())
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if !got.Equal(tt.want) || n != tt.wantN {
+				t.Errorf("next = %v #%d, want %v #%d", got, n, tt.want, tt.wantN)
+			}
+		})
+	}
+}
+
+func TestNextOccurrenceWithoutDueDate(t *testing.T) {
+	// an item without a due date recurs from the time it is completed
+	clock := fixedClock(time.Date(2025, 12, 31, 23, 0, 0, 0, time.UTC))
+	rule, _ := ParseRecurrence("FREQ=DAILY")
+	got, n, ok := nextOccurrence(rule, &TodoItem{Occurrence: 1}, clock.Now())
+	if want := time.Date(2026, 1, 1, 23, 0, 0, 0, time.UTC); !ok || !got.Equal(want) || n != 2 {
+		t.Errorf("next = %v #%d %v, want %v #2", got, n, ok, want)
+	}
+}
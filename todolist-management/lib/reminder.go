@@ -0,0 +1,112 @@
+package todolist
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Reminder is the event emitted for an incomplete item whose due date is near
+type Reminder struct {
+	Item *TodoItem `json:"item"`
+	At   time.Time `json:"at"`
+}
+
+// Notifier delivers reminders, the scheduler retries an item on its next run
+// when Notify returns an error
+type Notifier interface {
+	Notify(ctx context.Context, r *Reminder) error
+}
+
+// LogNotifier is the default Notifier, it writes reminders to a logger
+type LogNotifier struct {
+	Logger *slog.Logger
+}
+
+// Notify logs the ids of the item of the reminder, not its value, on the
+// default logger if none is set
+func (n *LogNotifier) Notify(ctx context.Context, r *Reminder) error {
+	logger := n.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.InfoContext(ctx, "reminder", "item_id", r.Item.ID, "list_id", r.Item.ListID)
+	return nil
+}
+
+// ReminderScheduler periodically looks for items due within Lead and sends
+// a single reminder for each of them through its Notifier
+type ReminderScheduler struct {
+	c        *Core
+	n        Notifier
+	Lead     time.Duration
+	Interval time.Duration
+}
+
+// NewReminderScheduler returns a scheduler reminding an hour before items are
+// due, checking every minute
+func NewReminderScheduler(c *Core, n Notifier) *ReminderScheduler {
+	if n == nil {
+		n = &LogNotifier{}
+	}
+	return &ReminderScheduler{c: c, n: n, Lead: time.Hour, Interval: time.Minute}
+}
+
+// Run checks for reminders every Interval until ctx is done
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.RunOnce(ctx, s.c.clock.Now()); err != nil {
+			slog.ErrorContext(ctx, "reminder scheduler error", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends the reminders of the items due before now+Lead which have
+// not been reminded yet, the reminders which failed being sent again on the
+// next run
+func (s *ReminderScheduler) RunOnce(ctx context.Context, now time.Time) error {
+	items, err := s.c.claimReminders(ctx, now.Add(s.Lead), now)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := s.n.Notify(ctx, &Reminder{Item: item, At: now}); err != nil {
+			slog.ErrorContext(ctx, "reminder notify error", "item_id", item.ID, "err", err)
+			if err := s.c.releaseReminder(ctx, item.ID, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// claimReminders marks the incomplete, not yet reminded items due before
+// until as reminded at now and returns them, archived lists and templates
+// aside. The rows claimed by another scheduler are skipped rather than
+// waited for, so that every reminder is sent by a single scheduler
+func (c *Core) claimReminders(ctx context.Context, until, now time.Time) ([]*TodoItem, error) {
+	const query = `UPDATE todolist_management.todo_items SET reminded_at = $2 WHERE id IN (
+			SELECT id FROM todolist_management.todo_items
+			WHERE completed IS NOT TRUE AND reminded_at IS NULL AND due_at <= $1
+			AND list_id IN (SELECT id FROM todolist_management.todo_lists WHERE NOT archived AND NOT template)
+			ORDER BY due_at, id FOR UPDATE SKIP LOCKED
+		) RETURNING ` + itemColumns
+	return c.queryItems(ctx, query, until, now)
+}
+
+// releaseReminder clears the claim made at the time at of an item whose
+// reminder failed, for the next run to send it
+func (c *Core) releaseReminder(ctx context.Context, id int64, at time.Time) error {
+	const query = `UPDATE todolist_management.todo_items SET reminded_at = NULL WHERE id = $1 AND reminded_at = $2`
+	if _, err := c.db.ExecContext(ctx, query, id, at); err != nil {
+		return err
+	}
+	return nil
+}
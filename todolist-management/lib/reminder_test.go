@@ -0,0 +1,55 @@
+package todolist
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/dbtest"
+)
+
+// failingNotifier fails the reminders of the items of fail
+type failingNotifier struct {
+	fail     map[int64]bool
+	notified []int64
+}
+
+func (n *failingNotifier) Notify(ctx context.Context, r *Reminder) error {
+	if n.fail[r.Item.ID] {
+		return errors.New("unreachable")
+	}
+	n.notified = append(n.notified, r.Item.ID)
+	return nil
+}
+
+func TestReminderSchedulerRunOnce(t *testing.T) {
+	now := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+	var claims []string
+	var released []driver.Value
+	db := dbtest.Open(func(query string, args []driver.Value) ([][]driver.Value, error) {
+		switch {
+		case strings.Contains(query, "SET reminded_at = $2"):
+			claims = append(claims, query)
+			return [][]driver.Value{itemRow(10, 1, 0, "milk", false), itemRow(11, 1, 0, "bread", false)}, nil
+		case strings.Contains(query, "SET reminded_at = NULL"):
+			released = append(released, args[0])
+		}
+		return nil, nil
+	})
+	n := &failingNotifier{fail: map[int64]bool{11: true}}
+	if err := NewReminderScheduler(NewCore(db), n).RunOnce(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+	if len(claims) != 1 || !strings.Contains(claims[0], "FOR UPDATE SKIP LOCKED") {
+		t.Errorf("claims = %q, want the items claimed once, skipping the locked ones", claims)
+	}
+	if len(n.notified) != 1 || n.notified[0] != 10 {
+		t.Errorf("notified = %v, want [10]", n.notified)
+	}
+	if len(released) != 1 || released[0] != int64(11) {
+		t.Errorf("released = %v, want the failed reminder of 11 released", released)
+	}
+}
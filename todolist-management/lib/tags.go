@@ -0,0 +1,188 @@
+package todolist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// ErrTag is returned for a tag name which is not valid
+var ErrTag = errors.New("invalid tag")
+
+// tagPattern is what a tag looks like once normalized, e.g. waiting-on-review
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// TagFilter selects the items having All of its Tags, or Any of them
+type TagFilter struct {
+	Tags []string
+	Any  bool
+}
+
+// normalizeTags lower cases and de-duplicates tags, checking each of them
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !tagPattern.MatchString(tag) {
+			return nil, ErrTag
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			out = append(out, tag)
+		}
+	}
+	return out, nil
+}
+
+// args returns the tags and the number of them an item must have to match
+// the filter, a nil filter matching every item
+func (f *TagFilter) args() (interface{}, int) {
+	if f == nil || len(f.Tags) == 0 {
+		return pq.Array([]string{}), 0
+	}
+	if f.Any {
+		return pq.Array(f.Tags), 1
+	}
+	return pq.Array(f.Tags), len(f.Tags)
+}
+
+// tagMatch is the condition on todo_items matching the TagFilter args $1, $2
+const tagMatch = `($2::int = 0 OR (SELECT COUNT(*) FROM todolist_management.item_tags it JOIN todolist_management.tags t ON t.id = it.tag_id
+	WHERE it.item_id = todolist_management.todo_items.id AND t.name = ANY($1)) >= $2)`
+
+// GetItemsByTags returns the items of all the lists matching the filter, the
+// ones of archived lists only if asked to and never the ones of templates
+func (c *Core) GetItemsByTags(ctx context.Context, filter *TagFilter, archived bool) (_ []*TodoItem, err error) {
+	ctx, end := startOperation(ctx, "GetItemsByTags")
+	defer end(&err)
+	tags, err := normalizeTags(filter.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) == 0 {
+		return []*TodoItem{}, nil
+	}
+	filter = &TagFilter{Tags: tags, Any: filter.Any}
+	names, n := filter.args()
+
+	const query = `SELECT ` + itemColumns + ` FROM todolist_management.todo_items WHERE ` + tagMatch + `
+		AND list_id IN (SELECT id FROM todolist_management.todo_lists WHERE NOT template AND ($3 OR NOT archived)) ORDER BY list_id, position, id`
+	return c.queryItems(ctx, query, names, n, archived)
+}
+
+// AddTodoItemTags tags an item, tags it already has are left as they are
+func (c *Core) AddTodoItemTags(ctx context.Context, id int64, tags []string) (_ *TodoItem, err error) {
+	ctx, end := startOperation(ctx, "AddTodoItemTags")
+	defer end(&err)
+	defer c.invalidate()
+	tags, err = normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockItem(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	if err := addTags(ctx, tx, id, tags); err != nil {
+		return nil, err
+	}
+
+	const touch = `UPDATE todolist_management.todo_items SET updated_at = now() WHERE id = $1 returning ` + itemColumns
+	item := &TodoItem{}
+	if err := scanItem(tx.QueryRowContext(ctx, touch, id), item); err != nil {
+		return nil, err
+	}
+	event := itemEvent(EventItemUpdated, item)
+	if err := c.record(ctx, tx, nil, event); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	c.publish(event)
+	return item, nil
+}
+
+// RemoveTodoItemTags removes tags from an item
+func (c *Core) RemoveTodoItemTags(ctx context.Context, id int64, tags []string) (_ *TodoItem, err error) {
+	ctx, end := startOperation(ctx, "RemoveTodoItemTags")
+	defer end(&err)
+	defer c.invalidate()
+	tags, err = normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockItem(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	const query = `DELETE FROM todolist_management.item_tags it USING todolist_management.tags t
+		WHERE it.tag_id = t.id AND it.item_id = $1 AND t.name = ANY($2)`
+	if _, err := tx.ExecContext(ctx, query, id, pq.Array(tags)); err != nil {
+		return nil, err
+	}
+
+	const touch = `UPDATE todolist_management.todo_items SET updated_at = now() WHERE id = $1 returning ` + itemColumns
+	item := &TodoItem{}
+	if err := scanItem(tx.QueryRowContext(ctx, touch, id), item); err != nil {
+		return nil, err
+	}
+	event := itemEvent(EventItemUpdated, item)
+	if err := c.record(ctx, tx, nil, event); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	c.publish(event)
+	return item, nil
+}
+
+// lockItem locks the row of an item for the rest of the transaction, given
+// its list can be written to
+func lockItem(ctx context.Context, tx *sql.Tx, id int64) error {
+	const query = `SELECT list_id FROM todolist_management.todo_items WHERE id = $1 FOR UPDATE`
+	lid := int64(0)
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&lid); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrItemNotFound
+		}
+		return err
+	}
+	return checkList(ctx, tx, lid)
+}
+
+// addTags creates the missing tags and attaches all of them to an item
+func addTags(ctx context.Context, tx *sql.Tx, id int64, tags []string) error {
+	const tagQuery = `INSERT INTO todolist_management.tags (name) SELECT unnest($1::text[]) ON CONFLICT (name) DO NOTHING`
+	const itemQuery = `INSERT INTO todolist_management.item_tags (item_id, tag_id)
+		SELECT $1, id FROM todolist_management.tags WHERE name = ANY($2) ON CONFLICT DO NOTHING`
+	if len(tags) == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, tagQuery, pq.Array(tags)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, itemQuery, id, pq.Array(tags)); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package todolist
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+// Template errors
+var (
+	ErrNotTemplate     = errors.New("list is not a template")
+	ErrMissingVariable = errors.New("missing template variable")
+)
+
+// templateVariableRef matches a {{variable}} reference of a template
+var templateVariableRef = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// CloneOptions are the options of a list clone, a clone of a list named
+// "Name" is named "Name (copy)" unless Name is given
+type CloneOptions struct {
+	Name           string `json:"name"`
+	ResetCompleted bool   `json:"reset_completed"`
+	Template       bool   `json:"template"`
+}
+
+// CloneTodoList creates a new list with copies of the items of a list, the
+// clone is a template if opts.Template is set and has the owner of the list
+func (c *Core) CloneTodoList(ctx context.Context, id int64, opts *CloneOptions) (_ *TodoList, err error) {
+	ctx, end := startOperation(ctx, "CloneTodoList")
+	defer end(&err)
+	src, err := c.GetTodoList(ctx, id, nil)
+	if err != nil {
+		return nil, err
+	}
+	name := opts.Name
+	if name == "" {
+		name = src.Name + " (copy)"
+	}
+	list := &TodoList{Name: name}
+	list.Template, list.OwnerID = opts.Template, src.OwnerID
+	list.Items, err = copyItems(src.Items, opts.ResetCompleted, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.AddTodoList(ctx, list)
+}
+
+// InstantiateTemplate creates a new list from a template, owned by the owner
+// of the template, the {{variable}} references in the name and the item
+// values being replaced by vars
+func (c *Core) InstantiateTemplate(ctx context.Context, id int64, name string, vars map[string]string) (_ *TodoList, err error) {
+	ctx, end := startOperation(ctx, "InstantiateTemplate")
+	defer end(&err)
+	src, err := c.GetTodoList(ctx, id, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !src.Template {
+		return nil, ErrNotTemplate
+	}
+	subst := func(s string) (string, error) {
+		var missing error
+		out := templateVariableRef.ReplaceAllStringFunc(s, func(ref string) string {
+			v, ok := vars[templateVariableRef.FindStringSubmatch(ref)[1]]
+			if !ok {
+				missing = ErrMissingVariable
+			}
+			return v
+		})
+		return out, missing
+	}
+	if name == "" {
+		name = src.Name
+	}
+	if name, err = subst(name); err != nil {
+		return nil, err
+	}
+	list := &TodoList{Name: name}
+	list.OwnerID = src.OwnerID
+	list.Items, err = copyItems(src.Items, true, subst)
+	if err != nil {
+		return nil, err
+	}
+	return c.AddTodoList(ctx, list)
+}
+
+// copyItems deep copies an item tree for insertion, applying subst to the
+// item values when given
+func copyItems(items []*TodoItem, reset bool, subst func(string) (string, error)) ([]*TodoItem, error) {
+	out := make([]*TodoItem, 0, len(items))
+	for _, item := range items {
+		cp := &TodoItem{
+			Value:      item.Value,
+			Completed:  item.Completed && !reset,
+			DueAt:      item.DueAt,
+			Priority:   item.Priority,
+			Recurrence: item.Recurrence,
+			Tags:       item.Tags,
+		}
+		if subst != nil {
+			value, err := subst(item.Value)
+			if err != nil {
+				return nil, err
+			}
+			cp.Value = value
+		}
+		children, err := copyItems(item.Children, reset, subst)
+		if err != nil {
+			return nil, err
+		}
+		cp.Children = children
+		out = append(out, cp)
+	}
+	return out, nil
+}
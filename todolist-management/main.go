@@ -9,12 +9,35 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	_ "embed"
 	"encoding/json"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/Shivam010/go-rest-api/todolist-management/lib"
+	"github.com/Shivam010/go-rest-api/user-management/lib"
+
+	"github.com/Shivam010/go-rest-api/cache"
+	"github.com/Shivam010/go-rest-api/changes"
+	"github.com/Shivam010/go-rest-api/cors"
+	"github.com/Shivam010/go-rest-api/health"
+	"github.com/Shivam010/go-rest-api/httperr"
+	"github.com/Shivam010/go-rest-api/idempotency"
+	"github.com/Shivam010/go-rest-api/logging"
+	"github.com/Shivam010/go-rest-api/metrics"
+	"github.com/Shivam010/go-rest-api/openapi"
+	"github.com/Shivam010/go-rest-api/ratelimit"
+	"github.com/Shivam010/go-rest-api/server"
+	"github.com/Shivam010/go-rest-api/tracing"
+	"github.com/Shivam010/go-rest-api/webhooks"
 
 	_ "github.com/lib/pq"
 )
@@ -29,19 +52,6 @@ func NewTodoListManagement(c *todolist.Core) *TodoListManagement {
 	return &TodoListManagement{c}
 }
 
-// Ex ...
-func (t *TodoListManagement) Ex(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
-		http.Error(w, "404 not found.", http.StatusNotFound)
-		return
-	}
-	if err := t.c.Ex("name"); err != nil {
-		InternalServerError(w, err)
-		return
-	}
-	ReturnJSONEncoded(w, "Ex Call")
-}
-
 // AddDeleteOrEdit ...
 func (t *TodoListManagement) AddDeleteOrEdit(w http.ResponseWriter, r *http.Request) {
 	if r.Method == "POST" {
@@ -51,7 +61,7 @@ func (t *TodoListManagement) AddDeleteOrEdit(w http.ResponseWriter, r *http.Requ
 	} else if r.Method == "PATCH" {
 		t.EditTodoListName(w, r)
 	} else {
-		http.Error(w, "404 not found.", http.StatusNotFound)
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
 	}
 }
 
@@ -59,55 +69,55 @@ func (t *TodoListManagement) AddDeleteOrEdit(w http.ResponseWriter, r *http.Requ
 func (t *TodoListManagement) AddTodoList(w http.ResponseWriter, r *http.Request) {
 	list := &todolist.TodoList{}
 	if err := json.NewDecoder(r.Body).Decode(list); err != nil {
-		InternalServerError(w, err)
+		InternalServerError(w, r, err)
 		return
 	}
 
-	obj, err := t.c.AddTodoList(list)
+	obj, err := t.c.AddTodoList(r.Context(), list)
 	if err != nil {
-		InternalServerError(w, err)
+		InternalServerError(w, r, err)
 		return
 	}
-	ReturnJSONEncoded(w, obj)
+	ReturnJSONEncoded(w, r, obj)
 }
 
 // DeleteTodoList ...
 func (t *TodoListManagement) DeleteTodoList(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
 	if err != nil {
-		InternalServerError(w, err)
+		InternalServerError(w, r, err)
 		return
 	}
-	if err := t.c.DeleteTodoList(id); err != nil {
-		InternalServerError(w, err)
+	if err := t.c.DeleteTodoList(r.Context(), id); err != nil {
+		InternalServerError(w, r, err)
 		return
 	}
-	ReturnJSONEncoded(w, empty{})
+	ReturnJSONEncoded(w, r, empty{})
 }
 
 // EditTodoListName ...
 func (t *TodoListManagement) EditTodoListName(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
 	if err != nil {
-		InternalServerError(w, err)
+		InternalServerError(w, r, err)
 		return
 	}
 	list := &todolist.TodoList{}
 	if err := json.NewDecoder(r.Body).Decode(list); err != nil {
-		InternalServerError(w, err)
+		InternalServerError(w, r, err)
 		return
 	}
-	if err := t.c.EditTodoListName(id, list.Name); err != nil {
-		InternalServerError(w, err)
+	if err := t.c.EditTodoListName(r.Context(), id, list.Name); err != nil {
+		InternalServerError(w, r, err)
 		return
 	}
-	ReturnJSONEncoded(w, empty{})
+	ReturnJSONEncoded(w, r, empty{})
 }
 
 // AddTodoItem ...
 func (t *TodoListManagement) AddTodoItem(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
-		http.Error(w, "404 not found.", http.StatusNotFound)
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
 		return
 	}
 	type Req struct {
@@ -116,111 +126,602 @@ func (t *TodoListManagement) AddTodoItem(w http.ResponseWriter, r *http.Request)
 	}
 	req := &Req{}
 	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
-		InternalServerError(w, err)
+		InternalServerError(w, r, err)
 		return
 	}
-	item, err := t.c.AddTodoItem(req.Lid, req.Item)
+	item, err := t.c.AddTodoItem(r.Context(), req.Lid, req.Item)
 	if err != nil {
-		InternalServerError(w, err)
+		InternalServerError(w, r, err)
 		return
 	}
-	ReturnJSONEncoded(w, item)
+	ReturnJSONEncoded(w, r, item)
 }
 
-// DeleteTodoListItem ...
+// DeleteTodoListItem deletes an item, its children are deleted along with
+// it for ?children=cascade or else moved up to its parent
 func (t *TodoListManagement) DeleteTodoListItem(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "DELETE" {
-		http.Error(w, "404 not found.", http.StatusNotFound)
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
 		return
 	}
 	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
 	if err != nil {
-		InternalServerError(w, err)
+		InternalServerError(w, r, err)
 		return
 	}
-	if err := t.c.DeleteTodoListItem(id); err != nil {
-		InternalServerError(w, err)
+	cascade := r.URL.Query().Get("children") == "cascade"
+	if err := t.c.DeleteTodoListItem(r.Context(), id, cascade); err != nil {
+		InternalServerError(w, r, err)
 		return
 	}
-	ReturnJSONEncoded(w, empty{})
+	ReturnJSONEncoded(w, r, empty{})
 }
 
 // GetTodoListItem ...
 func (t *TodoListManagement) GetTodoListItem(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
-		http.Error(w, "404 not found.", http.StatusNotFound)
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
 		return
 	}
 	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
 	if err != nil {
-		InternalServerError(w, err)
+		InternalServerError(w, r, err)
 		return
 	}
-	item, err := t.c.GetTodoListItem(id)
+	item, err := t.c.GetTodoListItem(r.Context(), id)
 	if err != nil {
-		InternalServerError(w, err)
+		InternalServerError(w, r, err)
 		return
 	}
-	ReturnJSONEncoded(w, item)
+	ReturnJSONEncoded(w, r, item)
 }
 
 // UpdateTodoItem ...
 func (t *TodoListManagement) UpdateTodoItem(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "PUT" {
-		http.Error(w, "404 not found.", http.StatusNotFound)
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	// only the fields present in the body are updated
+	body := json.RawMessage{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	present := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &present); err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	fields := make([]string, 0, len(present))
+	for field := range present {
+		fields = append(fields, field)
+	}
+	item := &todolist.TodoItem{}
+	if err := json.Unmarshal(body, item); err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	if err := t.c.UpdateTodoItem(r.Context(), item, fields...); err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	ReturnJSONEncoded(w, r, empty{})
+}
+
+// UpdateTodoSeries ...
+func (t *TodoListManagement) UpdateTodoSeries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "PUT" {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		InternalServerError(w, r, err)
 		return
 	}
 	item := &todolist.TodoItem{}
 	if err := json.NewDecoder(r.Body).Decode(item); err != nil {
-		InternalServerError(w, err)
+		InternalServerError(w, r, err)
 		return
 	}
-	if err := t.c.UpdateTodoItem(item); err != nil {
-		InternalServerError(w, err)
+	if err := t.c.UpdateTodoSeries(r.Context(), id, item); err != nil {
+		InternalServerError(w, r, err)
 		return
 	}
-	ReturnJSONEncoded(w, empty{})
+	ReturnJSONEncoded(w, r, empty{})
+}
+
+// AddOrRemoveTags adds the tags of the request body to an item for POST, and
+// removes the ?tag= tags from it for DELETE
+func (t *TodoListManagement) AddOrRemoveTags(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	var item *todolist.TodoItem
+	if r.Method == "POST" {
+		type Req struct {
+			Tags []string `json:"tags"`
+		}
+		req := &Req{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			InternalServerError(w, r, err)
+			return
+		}
+		item, err = t.c.AddTodoItemTags(r.Context(), id, req.Tags)
+	} else if r.Method == "DELETE" {
+		item, err = t.c.RemoveTodoItemTags(r.Context(), id, r.URL.Query()["tag"])
+	} else {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	ReturnJSONEncoded(w, r, item)
+}
+
+// GetItemsByTags ...
+func (t *TodoListManagement) GetItemsByTags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	filter := TagFilter(r)
+	if filter == nil {
+		httperr.Error(w, "tag is required", http.StatusBadRequest)
+		return
+	}
+	items, err := t.c.GetItemsByTags(r.Context(), filter, Include(r, "archived"))
+	if err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	ReturnJSONEncoded(w, r, items)
+}
+
+// ListTodoLists returns a page of the lists, ?limit= (20 by default, at
+// most 100) from ?offset=, sorted by ?sort= in ?order=asc or desc
+func (t *TodoListManagement) ListTodoLists(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	q := r.URL.Query()
+	opts := &todolist.ListOptions{
+		Limit:     20,
+		Sort:      q.Get("sort"),
+		Desc:      q.Get("order") == "desc",
+		Archived:  Include(r, "archived"),
+		Templates: Include(r, "templates"),
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > 100 {
+			httperr.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		opts.Limit = limit
+	}
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			httperr.Error(w, "invalid offset", http.StatusBadRequest)
+			return
+		}
+		opts.Offset = offset
+	}
+	page, err := t.c.ListTodoLists(r.Context(), opts)
+	if err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	ReturnJSONEncoded(w, r, page)
+}
+
+// ArchiveTodoList ...
+func (t *TodoListManagement) ArchiveTodoList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	if err := t.c.ArchiveTodoList(r.Context(), id); err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	ReturnJSONEncoded(w, r, empty{})
+}
+
+// UnarchiveTodoList ...
+func (t *TodoListManagement) UnarchiveTodoList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	if err := t.c.UnarchiveTodoList(r.Context(), id); err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	ReturnJSONEncoded(w, r, empty{})
+}
+
+// CloneTodoList ...
+func (t *TodoListManagement) CloneTodoList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	opts := &todolist.CloneOptions{}
+	if err := json.NewDecoder(r.Body).Decode(opts); err != nil && err != io.EOF {
+		InternalServerError(w, r, err)
+		return
+	}
+	list, err := t.c.CloneTodoList(r.Context(), id, opts)
+	if err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	ReturnJSONEncoded(w, r, list)
+}
+
+// InstantiateTemplate ...
+func (t *TodoListManagement) InstantiateTemplate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	type Req struct {
+		Name      string            `json:"name"`
+		Variables map[string]string `json:"variables"`
+	}
+	req := &Req{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	list, err := t.c.InstantiateTemplate(r.Context(), id, req.Name, req.Variables)
+	if err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	ReturnJSONEncoded(w, r, list)
 }
 
 // GetTodoList ...
 func (t *TodoListManagement) GetTodoList(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
-		http.Error(w, "404 not found.", http.StatusNotFound)
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
 		return
 	}
 	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
 	if err != nil {
-		InternalServerError(w, err)
+		InternalServerError(w, r, err)
+		return
+	}
+	list, err := t.c.GetTodoList(r.Context(), id, TagFilter(r))
+	if err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	ReturnJSONEncoded(w, r, list)
+}
+
+// MoveTodoItem ...
+func (t *TodoListManagement) MoveTodoItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	move := &todolist.ItemMove{}
+	if err := json.NewDecoder(r.Body).Decode(move); err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	item, err := t.c.MoveTodoItem(r.Context(), id, move)
+	if err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	ReturnJSONEncoded(w, r, item)
+}
+
+// GetOverdueItems ...
+func (t *TodoListManagement) GetOverdueItems(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	items, err := t.c.GetOverdueItems(r.Context(), time.Now(), Include(r, "archived"))
+	if err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	ReturnJSONEncoded(w, r, items)
+}
+
+// GetItemsDueToday returns the items due today in the time zone given by the
+// tz query parameter, UTC by default
+func (t *TodoListManagement) GetItemsDueToday(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	loc, err := time.LoadLocation(r.URL.Query().Get("tz"))
+	if err != nil {
+		httperr.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	now := time.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	items, err := t.c.GetItemsDueBetween(r.Context(), from, from.AddDate(0, 0, 1), Include(r, "archived"))
+	if err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+	ReturnJSONEncoded(w, r, items)
+}
+
+// ListEvents streams the changes of a list as Server-Sent Events, resuming
+// after the Last-Event-ID header or last_event_id query parameter when given;
+// a reset event tells the client to read the list again
+func (t *TodoListManagement) ListEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
 		return
 	}
-	list, err := t.c.GetTodoList(id)
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
-		InternalServerError(w, err)
+		InternalServerError(w, r, err)
 		return
 	}
-	ReturnJSONEncoded(w, list)
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+	sub, replay, err := t.c.SubscribeTodoList(r.Context(), id, lastID)
+	if err != nil && err != todolist.ErrEventsLost {
+		InternalServerError(w, r, err)
+		return
+	}
+	defer sub.Close()
+
+	// the stream outlives the write timeout of the server
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	io.WriteString(w, "retry: 3000\n\n")
+	if err == todolist.ErrEventsLost {
+		io.WriteString(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, e := range replay {
+		if err := WriteEvent(w, e); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	// the stream ends when the server shuts down, the client reconnects
+	ctx, cancel := server.StreamContext(r.Context())
+	defer cancel()
+	ping := time.NewTicker(eventsPing)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-sub.C:
+			if !ok {
+				// too far behind, the client reconnects with its last event id
+				return
+			}
+			if err := WriteEvent(w, e); err != nil {
+				return
+			}
+		case <-ping.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
 }
 
+// routes returns the mux of the api, every request being rate limited by
+// limiter, bounded by a timeout and replayed by keeper to its retries when
+// sent with an Idempotency-Key; the mux routes every path of the OpenAPI
+// document spec
+func routes(db *sql.DB, core *todolist.Core, limiter *ratelimit.Limiter, keeper *idempotency.Keeper) (*openapi.Mux, error) {
+	tdm := NewTodoListManagement(core)
+	mux := openapi.NewMux()
+	idempotent := Idempotent(keeper)
+	limit := RateLimit(limiter)
+	ipLimit := IPRateLimit(limiter)
+	timeout := Timeout(server.RequestTimeout)
+	mux.HandleFunc("/todolist", Wrapper(tdm.AddDeleteOrEdit, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))                   // POST | DELETE | PATCH
+	mux.HandleFunc("/todolist/addItem", Wrapper(tdm.AddTodoItem, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))               // POST
+	mux.HandleFunc("/todolist/deleteItem", Wrapper(tdm.DeleteTodoListItem, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))     // DELETE
+	mux.HandleFunc("/todolist/getItem", Wrapper(tdm.GetTodoListItem, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))           // GET
+	mux.HandleFunc("/todolist/updateItem", Wrapper(tdm.UpdateTodoItem, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))         // PUT
+	mux.HandleFunc("/todolist/getList", Wrapper(tdm.GetTodoList, idempotent, limit, timeout, Instrument))                                             // Wrapper(tdm.GetTodoList, idempotent, limit, BasicAuthentication, timeout, Instrument)) GET
+	mux.HandleFunc("/todolist/items/{id}/move", Wrapper(tdm.MoveTodoItem, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))      // POST
+	mux.HandleFunc("/todolist/items/overdue", Wrapper(tdm.GetOverdueItems, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))     // GET
+	mux.HandleFunc("/todolist/items/dueToday", Wrapper(tdm.GetItemsDueToday, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))   // GET
+	mux.HandleFunc("/todolist/series/{id}", Wrapper(tdm.UpdateTodoSeries, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))      // PUT
+	mux.HandleFunc("/todolist/items/{id}/tags", Wrapper(tdm.AddOrRemoveTags, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))   // POST | DELETE
+	mux.HandleFunc("/items", Wrapper(tdm.GetItemsByTags, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))                       // GET
+	mux.HandleFunc("/lists", Wrapper(tdm.ListTodoLists, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))                        // GET
+	mux.HandleFunc("/lists/{id}/archive", Wrapper(tdm.ArchiveTodoList, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))         // POST
+	mux.HandleFunc("/lists/{id}/unarchive", Wrapper(tdm.UnarchiveTodoList, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))     // POST
+	mux.HandleFunc("/lists/{id}/clone", Wrapper(tdm.CloneTodoList, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))             // POST
+	mux.HandleFunc("/lists/{id}/instantiate", Wrapper(tdm.InstantiateTemplate, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument)) // POST
+	mux.HandleFunc("/lists/{id}/events", Wrapper(tdm.ListEvents, limit, BasicAuthentication, ipLimit, Instrument))                                    // GET, streamed without a timeout
+
+	// webhook subscriptions, shared with the user-management service
+	hooks := webhooks.NewAPI(webhooks.NewStore(db))
+	mux.HandleFunc("/webhooks", Wrapper(hooks.Subscriptions, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))              // GET | POST
+	mux.HandleFunc("/webhooks/{id}", Wrapper(hooks.Subscription, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))          // GET | PUT | DELETE
+	mux.HandleFunc("/webhooks/{id}/deliveries", Wrapper(hooks.Deliveries, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument)) // GET
+
+	// change feed of the lists, items and users, shared with the
+	// user-management service
+	mux.HandleFunc("/changes", Wrapper(changes.NewFeed(db).Changes, limit, BasicAuthentication, ipLimit, Instrument)) // GET, streamed without a timeout
+
+	// GraphQL api of the lists and of the users of the user-management
+	// service, whose store is left uncached since the users are changed by it
+	gql, err := NewGraphQL(core, users.NewStore(db))
+	if err != nil {
+		return nil, err
+	}
+	mux.HandleFunc("/graphql", Wrapper(gql.Query, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument)) // GET | POST
+
+	// probes of the orchestrator, also served by the admin server
+	mux.HandleFunc("/healthz", health.Live)                 // GET
+	mux.HandleFunc("/readyz", health.NewChecker(db).Status) // GET
+
+	// metrics, behind auth, also served by the admin server
+	mux.HandleFunc("/metrics", Wrapper(metrics.Handler, BasicAuthentication, ipLimit)) // GET
+
+	// OpenAPI document of the api and its docs
+	mux.HandleFunc("/openapi.json", Wrapper(openapi.Spec(spec), limit, Instrument)) // GET
+	mux.HandleFunc("/docs", Wrapper(openapi.Docs, limit, Instrument))               // GET
+	mux.HandleFunc("/docs/{file}", Wrapper(openapi.Assets, limit, Instrument))      // GET
+	return mux, nil
+}
+
+// spec is the OpenAPI document of the api, describing every route of its mux
+//
+//go:embed openapi.json
+var spec []byte
+
 func main() {
+	// structured logs, of the level of LOG_LEVEL
+	slog.SetDefault(logging.New(os.Stdout, logging.ParseLevel(os.Getenv("LOG_LEVEL"))))
+
+	// traces, exported as set up by the OTEL_ environment
+	shutdownTracing, err := tracing.Setup("todolist-management")
+	if err != nil {
+		slog.Error("tracing setup error", "err", err)
+		return
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), server.ShutdownTimeout)
+		defer cancel()
+		shutdownTracing(ctx)
+	}()
+
 	// database connection
 	db, err := DatabaseConnection()
 	if err != nil {
+		slog.Error("database error", "err", err)
 		return
 	}
 	defer db.Close()
 
-	tdm := NewTodoListManagement(todolist.NewCore(db))
+	core := todolist.NewCore(db)
+	core.SetCache(cache.NewLRU("todolist", cacheSize, cacheTTL))
+
+	// reminders of the items about to be due and deliveries of the webhooks,
+	// until the server shuts down
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		todolist.NewReminderScheduler(core, &todolist.LogNotifier{}).Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		webhooks.NewDispatcher(db).Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// rate limits of the clients, by route, behind the TRUSTED_PROXIES
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), strings.Split(os.Getenv("TRUSTED_PROXIES"), ","))
+	if err != nil {
+		slog.Error("rate limiter setup error", "err", err)
+		return
+	}
+	limiter.Routes["/todolist"] = ratelimit.PerMinute(30)
+	limiter.Routes["/todolist/addItem"] = ratelimit.PerMinute(30)
+	limiter.Routes["/lists/{id}/clone"] = ratelimit.PerMinute(10)
+	limiter.Routes["/lists/{id}/instantiate"] = ratelimit.PerMinute(10)
+
+	// responses of the requests with an Idempotency-Key, kept for a day
+	keeper := idempotency.NewKeeper(idempotency.NewMemoryStore(), idempotencyTTL)
+
+	// api pattern handlers, the service not starting when they are out of
+	// step with the OpenAPI document
+	mux, err := routes(db, core, limiter, keeper)
+	if err != nil {
+		slog.Error("routes setup error", "err", err)
+		return
+	}
+	if err := openapi.Check(spec, mux.Patterns()); err != nil {
+		slog.Error("openapi document error", "err", err)
+		return
+	}
+
+	// probes of the orchestrator and metrics, on the admin server
+	admin := server.AdminMux()
+	admin.HandleFunc("/healthz", health.Live)                // GET
+	admin.HandleFunc("/readyz", health.NewChecker(db).Ready) // GET
+	metrics.RegisterDBStats(db, "todolist")
+	admin.HandleFunc("/metrics", metrics.Handler) // GET
+
+	// gRPC api, over the same core
+	gs, err := server.NewGRPC(NewTodoListServer(core).Register, Authorized)
+	if err != nil {
+		slog.Error("grpc server setup error", "err", err)
+		return
+	}
 
-	// api pattern handlers
-	http.HandleFunc("/ex", tdm.Ex)                                                                // Wrapper(tdm.Ex, BasicAuthentication)) GET
-	http.HandleFunc("/todolist", Wrapper(tdm.AddDeleteOrEdit, BasicAuthentication))               // POST | DELETE | PATCH
-	http.HandleFunc("/todolist/addItem", Wrapper(tdm.AddTodoItem, BasicAuthentication))           // POST
-	http.HandleFunc("/todolist/deleteItem", Wrapper(tdm.DeleteTodoListItem, BasicAuthentication)) // DELETE
-	http.HandleFunc("/todolist/getItem", Wrapper(tdm.GetTodoListItem, BasicAuthentication))       // GET
-	http.HandleFunc("/todolist/updateItem", Wrapper(tdm.UpdateTodoItem, BasicAuthentication))     // PUT
-	http.HandleFunc("/todolist/getList", tdm.GetTodoList)                                         // Wrapper(tdm.GetTodoList, BasicAuthentication)) GET
+	// browser clients of the CORS_ allowed origins
+	origins, err := cors.FromEnv()
+	if err != nil {
+		slog.Error("cors setup error", "err", err)
+		return
+	}
 
-	if err := http.ListenAndServe(":8080", nil); err != nil {
-		log.Fatalf("server error: %v", err)
+	if err := server.Serve(tracing.Handler(logging.Handler(origins.Handler(mux))), admin, gs); err != nil {
+		slog.Error("server error", "err", err)
 	}
 }
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/dbtest"
+	"github.com/Shivam010/go-rest-api/httperr"
+	"github.com/Shivam010/go-rest-api/idempotency"
+	"github.com/Shivam010/go-rest-api/openapi"
+	"github.com/Shivam010/go-rest-api/ratelimit"
+	todolist "github.com/Shivam010/go-rest-api/todolist-management/lib"
+)
+
+func TestGetTodoList(t *testing.T) {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	db := dbtest.Open(func(query string, args []driver.Value) ([][]driver.Value, error) {
+		switch {
+		case strings.Contains(query, "WHERE l.id = $1"):
+			switch args[0].(int64) {
+			case 1:
+				return [][]driver.Value{{int64(1), "empty", false, false, int64(0), int64(0), created, created, nil}}, nil
+			case 2:
+				return [][]driver.Value{{int64(2), "groceries", false, false, int64(1), int64(0), created, created, nil}}, nil
+			}
+		case strings.Contains(query, "WHERE list_id = $3") && args[2].(int64) == 2:
+			return [][]driver.Value{{int64(10), int64(2), "milk", false, int64(1024), nil, "normal", nil, created, created, "", int64(10), int64(1), int64(0), []byte("{}")}}, nil
+		}
+		return nil, nil
+	})
+	tdm := NewTodoListManagement(todolist.NewCore(db))
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		status int
+		body   string
+	}{
+		{"missing list", "GET", "/todolist/getList?id=3", http.StatusNotFound, todolist.ErrNotFound.Error()},
+		{"empty list", "GET", "/todolist/getList?id=1", http.StatusOK, `"items":[]`},
+		{"populated list", "GET", "/todolist/getList?id=2", http.StatusOK, `"items":[{"id":10,"list_id":2,"value":"milk"`},
+		{"wrong method", "POST", "/todolist/getList?id=1", http.StatusNotFound, "404 not found."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tdm.GetTodoList(w, httptest.NewRequest(tt.method, tt.target, nil))
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if !strings.Contains(w.Body.String(), tt.body) {
+				t.Errorf("body = %q, want %q in it", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestRoutesMatchOpenAPI(t *testing.T) {
+	db := dbtest.Open(func(query string, args []driver.Value) ([][]driver.Value, error) {
+		return nil, nil
+	})
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux, err := routes(db, todolist.NewCore(db), limiter, idempotency.NewKeeper(idempotency.NewMemoryStore(), time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := openapi.Check(spec, mux.Patterns()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	db := dbtest.Open(func(query string, args []driver.Value) ([][]driver.Value, error) {
+		return nil, nil
+	})
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux, err := routes(db, todolist.NewCore(db), limiter, idempotency.NewKeeper(idempotency.NewMemoryStore(), time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the error responses of the document are the bodies the handlers write
+	doc := struct {
+		Components struct {
+			Responses map[string]struct {
+				Content map[string]json.RawMessage `json:"content"`
+			} `json:"responses"`
+		} `json:"components"`
+	}{}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		t.Fatal(err)
+	}
+	for name, res := range doc.Components.Responses {
+		if _, ok := res.Content["application/json"]; !ok || len(res.Content) != 1 {
+			t.Errorf("response %s is not declared as application/json", name)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("DELETE", "/openapi.json", nil))
+	body := &httperr.Body{}
+	if err := json.Unmarshal(w.Body.Bytes(), body); err != nil || w.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("body = %q, %v", w.Body, err)
+	}
+	if w.Code != http.StatusNotFound || body.Status != http.StatusNotFound || body.Error != "404 not found." {
+		t.Errorf("DELETE /openapi.json = %d, %+v", w.Code, body)
+	}
+}
+
+func TestInternalServerError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{todolist.ErrNotFound, http.StatusNotFound},
+		{fmt.Errorf("move: %w", todolist.ErrItemNotFound), http.StatusNotFound},
+		{fmt.Errorf("tag: %w", todolist.ErrTag), http.StatusPreconditionFailed},
+		{fmt.Errorf("update: %w", todolist.ErrArchived), http.StatusConflict},
+		{errors.New("connection reset"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		InternalServerError(w, httptest.NewRequest("GET", "/lists/1", nil), tt.err)
+		if w.Code != tt.want {
+			t.Errorf("InternalServerError(%v) = %d, want %d", tt.err, w.Code, tt.want)
+		}
+	}
+}
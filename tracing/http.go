@@ -0,0 +1,31 @@
+package tracing
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDHeader is the header of the trace ID of a response
+const TraceIDHeader = "X-Trace-ID"
+
+// Handler starts a server span for every request of next, continuing the
+// trace of its traceparent and tracestate headers, and returns the trace ID
+// in the X-Trace-ID header of the response. The span is named after the
+// pattern of the mux serving the request, once it is routed
+func Handler(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := TraceID(r.Context()); id != "" {
+			w.Header().Set(TraceIDHeader, id)
+		}
+		req := r.WithContext(r.Context())
+		next.ServeHTTP(w, req)
+		// the mux sets the pattern on the request it serves, otelhttp names
+		// the span after the pattern of its own
+		if r.Pattern = req.Pattern; r.Pattern != "" {
+			trace.SpanFromContext(r.Context()).SetAttributes(semconv.HTTPRoute(r.Pattern))
+		}
+	}), "http.request")
+}
@@ -0,0 +1,16 @@
+package tracing
+
+import (
+	"database/sql"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelsql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// OpenDB opens a database of a registered driver whose statements, prepared
+// or not, and transactions are traced as client spans of the current span
+// of their context, with the db.system.name attribute of the semantic
+// conventions the other spans follow
+func OpenDB(driverName, dsn string) (*sql.DB, error) {
+	return otelsql.Open(driverName, dsn, otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL))
+}
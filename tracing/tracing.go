@@ -0,0 +1,92 @@
+// Package tracing sets up the OpenTelemetry tracing of the services: the
+// SDK, following the W3C trace context and baggage of the requests, and
+// exporting the spans to stdout or to an OpenTelemetry collector over OTLP
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Setup sets up the tracing of a service from the environment:
+//   - OTEL_SERVICE_NAME, the name of the service, service by default
+//   - OTEL_TRACES_EXPORTER, none, the default, stdout or otlp
+//   - OTEL_EXPORTER_OTLP_ENDPOINT, the collector, http://localhost:4318 by default
+//   - OTEL_TRACES_SAMPLER_ARG, the ratio of the new traces sampled, 1 by default
+//
+// The traces started by a caller are sampled as the caller decided. The
+// returned func flushes the spans not yet exported.
+func Setup(service string) (func(context.Context) error, error) {
+	ratio := 1.0
+	if arg := os.Getenv("OTEL_TRACES_SAMPLER_ARG"); arg != "" {
+		f, err := strconv.ParseFloat(arg, 64)
+		if err != nil || f < 0 || f > 1 {
+			return nil, fmt.Errorf("tracing: invalid sampler ratio %q", arg)
+		}
+		ratio = f
+	}
+
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the service
+	res, err := resource.New(context.Background(),
+		resource.WithAttributes(semconv.ServiceName(service)),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: %w", err)
+	}
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	}
+
+	switch name := getenv("OTEL_TRACES_EXPORTER", "none"); name {
+	case "none":
+	case "stdout", "console":
+		exporter, err := stdouttrace.New()
+		if err != nil {
+			return nil, fmt.Errorf("tracing: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	case "otlp":
+		// the exporter reads OTEL_EXPORTER_OTLP_ENDPOINT itself
+		exporter, err := otlptracehttp.New(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("tracing: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("tracing: unknown exporter %q", name)
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp.Shutdown, nil
+}
+
+func getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// TraceID returns the hex trace ID of the current span of ctx, empty if none
+func TraceID(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
@@ -0,0 +1,110 @@
+package tracing
+
+import (
+	"context"
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Shivam010/go-rest-api/dbtest"
+)
+
+// record sets up the tracing and returns the recorder of its ended spans
+func record(t *testing.T) *tracetest.SpanRecorder {
+	t.Setenv("OTEL_TRACES_EXPORTER", "none")
+	shutdown, err := Setup("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { shutdown(context.Background()) })
+	rec := tracetest.NewSpanRecorder()
+	otel.GetTracerProvider().(*sdktrace.TracerProvider).RegisterSpanProcessor(rec)
+	return rec
+}
+
+func TestHandler(t *testing.T) {
+	rec := record(t)
+
+	var outgoing http.Header
+	mux := http.NewServeMux()
+	mux.HandleFunc("/lists/{id}", func(w http.ResponseWriter, r *http.Request) {
+		outgoing = http.Header{}
+		otel.GetTextMapPropagator().Inject(r.Context(), propagation.HeaderCarrier(outgoing))
+	})
+	req := httptest.NewRequest("GET", "/lists/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set("tracestate", "vendor=value")
+	w := httptest.NewRecorder()
+	Handler(mux).ServeHTTP(w, req)
+
+	if id := w.Header().Get(TraceIDHeader); id != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("%s = %q, want the trace of the traceparent", TraceIDHeader, id)
+	}
+	if state := outgoing.Get("tracestate"); state != "vendor=value" {
+		t.Errorf("propagated tracestate = %q, want vendor=value", state)
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("%d spans ended, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET /lists/{id}" {
+		t.Errorf("span name = %q, want GET /lists/{id}", span.Name())
+	}
+	if span.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("span parent = %s, want the span of the traceparent", span.Parent().SpanID())
+	}
+	route := ""
+	for _, attr := range span.Attributes() {
+		if attr.Key == "http.route" {
+			route = attr.Value.AsString()
+		}
+	}
+	if route != "/lists/{id}" {
+		t.Errorf("http.route = %q, want /lists/{id}", route)
+	}
+}
+
+func TestOpenDB(t *testing.T) {
+	rec := record(t)
+
+	db, err := OpenDB("dbtest", dbtest.Register(func(query string, args []driver.Value) ([][]driver.Value, error) {
+		return [][]driver.Value{{int64(1)}}, nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	stmt, err := db.PrepareContext(ctx, "SELECT id FROM todo_lists WHERE id = $1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var id int64
+	if err := stmt.QueryRowContext(ctx, 1).Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	stmt.Close()
+	parent.End()
+
+	names := map[string]bool{}
+	for _, span := range rec.Ended() {
+		if span.Parent().SpanID() == parent.SpanContext().SpanID() && span.SpanKind() == trace.SpanKindClient {
+			names[span.Name()] = true
+		}
+	}
+	for _, name := range []string{"db.Prepare", "stmt.Query"} {
+		if !names[name] {
+			t.Errorf("no %s span of the prepared statement, got %v", name, names)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/Shivam010/go-rest-api/logging"
+	"github.com/Shivam010/go-rest-api/proto/userspb"
+	"github.com/Shivam010/go-rest-api/tracing"
+	"github.com/Shivam010/go-rest-api/user-management/lib"
+)
+
+// UserServer serves the gRPC API of the user store
+type UserServer struct {
+	userspb.UnimplementedUserServiceServer
+	s *users.Store
+}
+
+// NewUserServer ...
+func NewUserServer(s *users.Store) *UserServer {
+	return &UserServer{s: s}
+}
+
+// Register registers the server on gs
+func (u *UserServer) Register(gs *grpc.Server) {
+	userspb.RegisterUserServiceServer(gs, u)
+}
+
+// CreateUser ...
+func (u *UserServer) CreateUser(ctx context.Context, req *userspb.User) (*userspb.User, error) {
+	user, err := u.s.CreateUser(ctx, userFromProto(req))
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return userToProto(user), nil
+}
+
+// GetUser ...
+func (u *UserServer) GetUser(ctx context.Context, req *userspb.GetUserRequest) (*userspb.User, error) {
+	user, err := u.s.GetUser(ctx, req.Id)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return userToProto(user), nil
+}
+
+// ListUsers ...
+func (u *UserServer) ListUsers(ctx context.Context, req *userspb.ListUsersRequest) (*userspb.ListUsersResponse, error) {
+	list, err := u.s.GetAllUsers(ctx)
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	res := &userspb.ListUsersResponse{Users: make([]*userspb.User, 0, len(list))}
+	for _, user := range list {
+		res.Users = append(res.Users, userToProto(user))
+	}
+	return res, nil
+}
+
+// EditUser ...
+func (u *UserServer) EditUser(ctx context.Context, req *userspb.User) (*emptypb.Empty, error) {
+	if err := u.s.EditUser(ctx, userFromProto(req)); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// DeleteUser ...
+func (u *UserServer) DeleteUser(ctx context.Context, req *userspb.DeleteUserRequest) (*emptypb.Empty, error) {
+	if err := u.s.DeleteUser(ctx, req.Id); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// grpcError returns the status of an error of the store, with the codes of
+// the statuses InternalServerError answers with
+func grpcError(ctx context.Context, err error) error {
+	if errors.Is(err, users.ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		logging.FromContext(ctx).Warn("request timed out", "err", err)
+		return status.Error(codes.DeadlineExceeded, "request timed out")
+	}
+	logging.FromContext(ctx).Error("request failed", "err", err)
+	msg := "internal error"
+	if id := tracing.TraceID(ctx); id != "" {
+		msg += " (trace id " + id + ")"
+	}
+	return status.Error(codes.Internal, msg)
+}
+
+// userToProto converts a user
+func userToProto(user *users.User) *userspb.User {
+	return &userspb.User{
+		Id:      user.ID,
+		Fname:   user.Fname,
+		Lname:   user.Lname,
+		Dob:     user.DOB,
+		Email:   user.Email,
+		PhoneNo: user.PhoneNo,
+	}
+}
+
+// userFromProto converts a user
+func userFromProto(pb *userspb.User) *users.User {
+	return &users.User{
+		ID:      pb.Id,
+		Fname:   pb.Fname,
+		Lname:   pb.Lname,
+		DOB:     pb.Dob,
+		Email:   pb.Email,
+		PhoneNo: pb.PhoneNo,
+	}
+}
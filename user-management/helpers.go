@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/auth"
+	"github.com/Shivam010/go-rest-api/httperr"
+	"github.com/Shivam010/go-rest-api/idempotency"
+	"github.com/Shivam010/go-rest-api/logging"
+	"github.com/Shivam010/go-rest-api/metrics"
+	"github.com/Shivam010/go-rest-api/ratelimit"
+	"github.com/Shivam010/go-rest-api/tracing"
+	"github.com/Shivam010/go-rest-api/user-management/lib"
+)
+
+// a generic empty struct to return a empty JSON object {} in response
+type empty struct {
+}
+
+const (
+	dbHost     = "localhost"
+	dbPort     = "5432"
+	dbUser     = "postgres"
+	dbPassword = "appointy"
+	dbName     = "test"
+)
+
+// database connection pool limits
+const (
+	dbMaxOpenConns    = 25
+	dbMaxIdleConns    = 5
+	dbConnMaxLifetime = 30 * time.Minute
+)
+
+// read cache limits, the values are cached for a short while since other
+// instances of the service don't purge the cache of this one
+const (
+	cacheSize = 1024
+	cacheTTL  = 30 * time.Second
+)
+
+// idempotencyTTL is how long the response of a request is replayed to its
+// retries
+const idempotencyTTL = 24 * time.Hour
+
+// InternalServerError is a generic internal server error handler
+func InternalServerError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, users.ErrNotFound) {
+		httperr.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		// the client went away, there is no one to answer to
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		httperr.Error(w, "503 Request Timed Out"+traceRef(r), http.StatusServiceUnavailable)
+		logging.FromContext(r.Context()).Warn("request timed out", "err", err)
+		return
+	}
+	httperr.Error(w, "500 Internal Server Error"+traceRef(r), http.StatusInternalServerError)
+	logging.FromContext(r.Context()).Error("request failed", "err", err)
+}
+
+// traceRef returns the reference to the trace of a request given in its
+// server errors
+func traceRef(r *http.Request) string {
+	if id := tracing.TraceID(r.Context()); id != "" {
+		return " (trace id " + id + ")"
+	}
+	return ""
+}
+
+// ReturnJSONEncoded is a generic response writer for interfaces in JSON content-type
+func ReturnJSONEncoded(w http.ResponseWriter, r *http.Request, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		InternalServerError(w, r, err)
+		return
+	}
+}
+
+// RequestHandlerFunc is the type defined to use the http Handler Function externally
+type RequestHandlerFunc func(http.ResponseWriter, *http.Request)
+
+// wrapper wraps the http request with sequence of middlewares provided
+func wrapper(fn RequestHandlerFunc, mds ...func(RequestHandlerFunc) RequestHandlerFunc) RequestHandlerFunc {
+	for _, md := range mds {
+		fn = md(fn)
+	}
+	return fn
+}
+
+// Authorized tells if the Basic Auth credentials of a request, over HTTP or
+// gRPC, are the ones of the api
+func Authorized(user, pass string) bool {
+	return user == "mavis" && pass == "shivam"
+}
+
+// BasicAuthentication middleware, the user of the verified credentials
+// being the principal of the request
+func BasicAuthentication(req RequestHandlerFunc) RequestHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Basic Realm: "Restricted"`)
+		user, pass, ok := r.BasicAuth()
+		if !ok || !Authorized(user, pass) {
+			httperr.Error(w, "Unauthorized Access", http.StatusUnauthorized)
+			return
+		}
+		req(w, r.WithContext(auth.WithPrincipal(r.Context(), user)))
+	}
+}
+
+// Timeout middleware cancels the context of the request, and so the SQL run
+// for it, after d
+func Timeout(d time.Duration) func(RequestHandlerFunc) RequestHandlerFunc {
+	return func(req RequestHandlerFunc) RequestHandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			req(w, r.WithContext(ctx))
+		}
+	}
+}
+
+// Instrument middleware records the metrics of the request
+func Instrument(req RequestHandlerFunc) RequestHandlerFunc {
+	return RequestHandlerFunc(metrics.Instrument(http.HandlerFunc(req)))
+}
+
+// RateLimit middleware limits the rate of the requests of every client, listed
+// before BasicAuthentication it runs once the user is authenticated
+func RateLimit(l *ratelimit.Limiter) func(RequestHandlerFunc) RequestHandlerFunc {
+	return func(req RequestHandlerFunc) RequestHandlerFunc {
+		return RequestHandlerFunc(l.Handler(http.HandlerFunc(req)))
+	}
+}
+
+// IPRateLimit middleware limits the rate of the requests of every client IP,
+// listed after BasicAuthentication it runs before the user is authenticated,
+// counting the requests whose credentials are refused
+func IPRateLimit(l *ratelimit.Limiter) func(RequestHandlerFunc) RequestHandlerFunc {
+	return func(req RequestHandlerFunc) RequestHandlerFunc {
+		return RequestHandlerFunc(l.IPHandler(http.HandlerFunc(req)))
+	}
+}
+
+// Idempotent middleware replays the response of a POST request to its
+// retries with the same Idempotency-Key, listed before BasicAuthentication
+// the keys are those of the authenticated user, the routes without it
+// scoping the keys by the payload of the requests
+func Idempotent(k *idempotency.Keeper) func(RequestHandlerFunc) RequestHandlerFunc {
+	return func(req RequestHandlerFunc) RequestHandlerFunc {
+		return RequestHandlerFunc(k.Handler(http.HandlerFunc(req)))
+	}
+}
+
+// DatabaseConnection returns a database connection setup, after checking the
+// database can be reached
+func DatabaseConnection() (*sql.DB, error) {
+	dbinfo := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", dbHost, dbPort, dbUser, dbPassword, dbName)
+	db, err := tracing.OpenDB("postgres", dbinfo)
+	if err != nil {
+		return nil, fmt.Errorf("db connection: %w", err)
+	}
+	db.SetMaxOpenConns(dbMaxOpenConns)
+	db.SetMaxIdleConns(dbMaxIdleConns)
+	db.SetConnMaxLifetime(dbConnMaxLifetime)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("db ping: %w", err)
+	}
+	return db, nil
+}
@@ -0,0 +1,45 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// metrics of the operations of the store
+var (
+	operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "users_store_operation_duration_seconds",
+		Help:    "Latency of the operations of the user store.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation"})
+	operationErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "users_store_operation_errors_total",
+		Help: "Number of the operations of the user store which failed.",
+	}, []string{"operation"})
+)
+
+// tracer of the operations of the store
+var tracer = otel.Tracer("github.com/Shivam010/go-rest-api/user-management/lib")
+
+// startOperation starts the span of an operation of the store, the returned
+// func ends it and records its latency, and its failure if *err is set, once
+// the operation returns
+func startOperation(ctx context.Context, op string) (context.Context, func(err *error)) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, "users."+op, trace.WithSpanKind(trace.SpanKindInternal))
+	return ctx, func(err *error) {
+		operationDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+		if *err != nil {
+			operationErrors.WithLabelValues(op).Inc()
+			span.RecordError(*err)
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,295 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strconv"
+
+	"github.com/Shivam010/go-rest-api/cache"
+	"github.com/Shivam010/go-rest-api/changes"
+	"github.com/Shivam010/go-rest-api/webhooks"
+
+	"github.com/lib/pq"
+)
+
+// Generic error messages
+var (
+	ErrNotFound = errors.New("user not found")
+)
+
+// Webhook event types, the data of an event is the user, of which only the
+// id is given once deleted
+const (
+	EventUserCreated = "user.created"
+	EventUserUpdated = "user.updated"
+	EventUserDeleted = "user.deleted"
+)
+
+// User Object
+type User struct {
+	ID      int64  `json:"id"`
+	Fname   string `json:"fname"`
+	Lname   string `json:"lname"`
+	DOB     string `json:"dob"`
+	Email   string `json:"email"`
+	PhoneNo int64  `json:"phoneno"`
+}
+
+// LogValue logs a user without its email, phone number and date of birth
+func (u *User) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int64("id", u.ID),
+		slog.String("fname", u.Fname),
+		slog.String("lname", u.Lname),
+	)
+}
+
+// userColumns are the users columns read by scanUser, in order
+const userColumns = `id, fname, COALESCE(lname, ''), COALESCE(dob, ''), email, phone_no`
+
+// scanner is implemented by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanUser reads the userColumns of a row into user
+func scanUser(row scanner, user *User) error {
+	return row.Scan(&user.ID, &user.Fname, &user.Lname, &user.DOB, &user.Email, &user.PhoneNo)
+}
+
+// Store ...
+type Store struct {
+	db    *sql.DB
+	cache cache.Cache
+}
+
+// NewStore implements User Management storage
+func NewStore(db *sql.DB) *Store {
+	return &Store{db, nil}
+}
+
+// SetCache makes the store cache the users it reads, every change made
+// through the store purging the cache; a nil cache caches nothing
+func (s *Store) SetCache(cache cache.Cache) {
+	s.cache = cache
+}
+
+// invalidate purges the cache once a change has been made
+func (s *Store) invalidate() {
+	if s.cache != nil {
+		s.cache.Purge()
+	}
+}
+
+// CreateUser creates a user
+func (s *Store) CreateUser(ctx context.Context, user *User) (_ *User, err error) {
+	ctx, end := startOperation(ctx, "CreateUser")
+	defer end(&err)
+	defer s.invalidate()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	const query = `INSERT INTO user_management.users(fname, lname, dob, email, phone_no) VALUES($1, $2, $3, $4, $5) returning id`
+	if err := tx.QueryRowContext(ctx, query, user.Fname, user.Lname, user.DOB, user.Email, user.PhoneNo).Scan(&user.ID); err != nil {
+		return nil, err
+	}
+	if err := webhooks.Enqueue(ctx, tx, EventUserCreated, user); err != nil {
+		return nil, err
+	}
+	if err := changes.Write(ctx, tx, changes.Scope{Users: []int64{user.ID}}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetUser returns a user
+func (s *Store) GetUser(ctx context.Context, id int64) (_ *User, err error) {
+	ctx, end := startOperation(ctx, "GetUser")
+	defer end(&err)
+	v, err := cache.Load(s.cache, "user:"+strconv.FormatInt(id, 10), func() (interface{}, error) {
+		return s.getUser(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// a copy, the cached users being shared by their readers
+	user := *v.(*User)
+	return &user, nil
+}
+
+// getUser reads a user from the database
+func (s *Store) getUser(ctx context.Context, id int64) (*User, error) {
+	const query = `SELECT ` + userColumns + ` FROM user_management.users WHERE id = $1`
+	user := &User{}
+	if err := scanUser(s.db.QueryRowContext(ctx, query, id), user); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetAllUsers returns all the users
+func (s *Store) GetAllUsers(ctx context.Context) (_ []*User, err error) {
+	ctx, end := startOperation(ctx, "GetAllUsers")
+	defer end(&err)
+	v, err := cache.Load(s.cache, "users", func() (interface{}, error) {
+		return s.getAllUsers(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// copies, the cached users being shared by their readers
+	list := make([]*User, len(v.([]*User)))
+	for i, user := range v.([]*User) {
+		cp := *user
+		list[i] = &cp
+	}
+	return list, nil
+}
+
+// getAllUsers reads all the users from the database
+func (s *Store) getAllUsers(ctx context.Context) ([]*User, error) {
+	const query = `SELECT ` + userColumns + ` FROM user_management.users ORDER BY id`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := []*User{}
+	for rows.Next() {
+		user := &User{}
+		if err := scanUser(rows, user); err != nil {
+			return nil, err
+		}
+		list = append(list, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetUsersByID returns the users of ids, by id; the ids of no user are left
+// out
+func (s *Store) GetUsersByID(ctx context.Context, ids []int64) (_ map[int64]*User, err error) {
+	ctx, end := startOperation(ctx, "GetUsersByID")
+	defer end(&err)
+	const query = `SELECT ` + userColumns + ` FROM user_management.users WHERE id = ANY($1)`
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	users := make(map[int64]*User, len(ids))
+	for rows.Next() {
+		user := &User{}
+		if err := scanUser(rows, user); err != nil {
+			return nil, err
+		}
+		users[user.ID] = user
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// EditUser updates a user
+func (s *Store) EditUser(ctx context.Context, user *User) (err error) {
+	ctx, end := startOperation(ctx, "EditUser")
+	defer end(&err)
+	defer s.invalidate()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	const query = `UPDATE user_management.users SET fname=$2, lname=$3, dob=$4, email=$5, phone_no=$6 WHERE id = $1`
+	res, err := tx.ExecContext(ctx, query, user.ID, user.Fname, user.Lname, user.DOB, user.Email, user.PhoneNo)
+	if err != nil {
+		return err
+	}
+	if err := mustAffect(res); err != nil {
+		return err
+	}
+	if err := webhooks.Enqueue(ctx, tx, EventUserUpdated, user); err != nil {
+		return err
+	}
+	if err := changes.Write(ctx, tx, changes.Scope{Users: []int64{user.ID}}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteUser deletes a user, its lists being left without an owner
+func (s *Store) DeleteUser(ctx context.Context, id int64) (err error) {
+	ctx, end := startOperation(ctx, "DeleteUser")
+	defer end(&err)
+	defer s.invalidate()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	lists, err := disown(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	const query = `DELETE FROM user_management.users WHERE id = $1`
+	res, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if err := mustAffect(res); err != nil {
+		return err
+	}
+	if err := webhooks.Enqueue(ctx, tx, EventUserDeleted, map[string]int64{"id": id}); err != nil {
+		return err
+	}
+	if err := changes.Write(ctx, tx, changes.Scope{Lists: lists, Users: []int64{id}}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// disown leaves the lists of a user without an owner ahead of its deletion,
+// which would otherwise do it unlogged, and returns their ids so that their
+// changes are logged
+func disown(ctx context.Context, tx *sql.Tx, id int64) ([]int64, error) {
+	const query = `UPDATE todolist_management.todo_lists SET owner_id = NULL, updated_at = now() WHERE owner_id = $1 RETURNING id`
+	rows, err := tx.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	lists := []int64{}
+	for rows.Next() {
+		var lid int64
+		if err := rows.Scan(&lid); err != nil {
+			return nil, err
+		}
+		lists = append(lists, lid)
+	}
+	return lists, rows.Err()
+}
+
+// mustAffect returns ErrNotFound when a statement changed no user
+func mustAffect(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
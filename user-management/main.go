@@ -10,228 +10,267 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	_ "embed"
 	"encoding/json"
-	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
 
-	_ "github.com/lib/pq"
-)
+	"github.com/Shivam010/go-rest-api/user-management/lib"
 
-var (
-	db  *sql.DB
-	err error
-)
+	"github.com/Shivam010/go-rest-api/cache"
+	"github.com/Shivam010/go-rest-api/changes"
+	"github.com/Shivam010/go-rest-api/cors"
+	"github.com/Shivam010/go-rest-api/health"
+	"github.com/Shivam010/go-rest-api/httperr"
+	"github.com/Shivam010/go-rest-api/idempotency"
+	"github.com/Shivam010/go-rest-api/logging"
+	"github.com/Shivam010/go-rest-api/metrics"
+	"github.com/Shivam010/go-rest-api/openapi"
+	"github.com/Shivam010/go-rest-api/ratelimit"
+	"github.com/Shivam010/go-rest-api/server"
+	"github.com/Shivam010/go-rest-api/tracing"
+	"github.com/Shivam010/go-rest-api/webhooks"
 
-const (
-	dbHost     = "localhost"
-	dbPort     = "5432"
-	dbUser     = "postgres"
-	dbPassword = "appointy"
-	dbName     = "test"
+	_ "github.com/lib/pq"
 )
 
-// User Object
-type User struct {
-	ID      int64  `json:"id"`
-	Fname   string `json:"fname"`
-	Lname   string `json:"lname"`
-	DOB     string `json:"dob"`
-	Email   string `json:"email"`
-	PhoneNo int64  `json:"phoneno"`
-}
-
-type empty struct {
-}
-
-// RequestHandlerFunc is the type defined to use the http Handler Function externally
-type RequestHandlerFunc func(http.ResponseWriter, *http.Request)
-
-// wrapper wraps the http request with sequence of middlewares provided
-func wrapper(fn RequestHandlerFunc, mds ...func(RequestHandlerFunc) RequestHandlerFunc) RequestHandlerFunc {
-	for _, md := range mds {
-		fn = md(fn)
-	}
-	return fn
+// UserManagement ...
+type UserManagement struct {
+	s *users.Store
 }
 
-// BasicAuthentication middleware
-func BasicAuthentication(req RequestHandlerFunc) RequestHandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("WWW-Authenticate", `Basic Realm: "Restricted"`)
-		user, pass, ok := r.BasicAuth()
-		if !ok || (ok && (user != "mavis" || pass != "shivam")) {
-			http.Error(w, "Unauthorized Access", http.StatusUnauthorized)
-			return
-		}
-		req(w, r)
-	}
+// NewUserManagement ...
+func NewUserManagement(s *users.Store) *UserManagement {
+	return &UserManagement{s}
 }
 
 // CreateUser create user
-func CreateUser(w http.ResponseWriter, r *http.Request) {
+func (u *UserManagement) CreateUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
-		http.Error(w, "404 not found.", http.StatusNotFound)
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
 		return
 	}
-	user := &User{}
+	user := &users.User{}
 	if err := json.NewDecoder(r.Body).Decode(user); err != nil {
-		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
-		log.Fatalf("error decoding data: %v", err)
+		InternalServerError(w, r, err)
 		return
 	}
-	fmt.Println(user)
-	const query = `INSERT INTO user_management.users(fname, lname, dob, email, phone_no) VALUES($1, $2, $3, $4, $5) returning id`
-	err = db.QueryRow(query, user.Fname, user.Lname, user.DOB, user.Email, user.PhoneNo).Scan(&user.ID)
+	logging.FromContext(r.Context()).Debug("creating user", "user", user)
+	user, err := u.s.CreateUser(r.Context(), user)
 	if err != nil {
-		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
-		log.Fatalf("create user error: %v", err)
-		return
-	}
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(user); err != nil {
-		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
-		log.Fatalf("error encoding data: %v", err)
+		InternalServerError(w, r, err)
 		return
 	}
+	ReturnJSONEncoded(w, r, user)
 }
 
 // GetUser returns a user
-func GetUser(w http.ResponseWriter, r *http.Request) {
+func (u *UserManagement) GetUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
-		http.Error(w, "404 not found.", http.StatusNotFound)
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
 		return
 	}
 	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
 	if err != nil {
-		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
-		log.Fatalf("error in string conversion: %v", err)
+		InternalServerError(w, r, err)
 		return
 	}
-	const query = `SELECT * FROM user_management.users WHERE id = $1`
-	row := db.QueryRow(query, id)
-	user := User{}
-	row.Scan(&user.Fname, &user.Lname, &user.DOB, &user.Email, &user.PhoneNo, &user.ID)
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(user); err != nil {
-		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
-		log.Fatalf("error encoding data: %v", err)
+	user, err := u.s.GetUser(r.Context(), id)
+	if err != nil {
+		InternalServerError(w, r, err)
 		return
 	}
+	ReturnJSONEncoded(w, r, user)
 }
 
 // GetAllUser returns all user
-func GetAllUser(w http.ResponseWriter, r *http.Request) {
+func (u *UserManagement) GetAllUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
-		http.Error(w, "404 not found.", http.StatusNotFound)
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
 		return
 	}
-	const query = `SELECT * FROM user_management.users`
-	rows, err := db.Query(query)
+	list, err := u.s.GetAllUsers(r.Context())
 	if err != nil {
-		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
-		log.Fatalf("error: %v", err)
-		return
-	}
-	list := []User{}
-	for rows.Next() {
-		user := User{}
-		rows.Scan(&user.Fname, &user.Lname, &user.DOB, &user.Email, &user.PhoneNo, &user.ID)
-		list = append(list, user)
-	}
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(list); err != nil {
-		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
-		log.Fatalf("error encoding data: %v", err)
+		InternalServerError(w, r, err)
 		return
 	}
+	ReturnJSONEncoded(w, r, list)
 }
 
 // EditUser edit a user
-func EditUser(w http.ResponseWriter, r *http.Request) {
+func (u *UserManagement) EditUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "PUT" {
-		http.Error(w, "404 not found.", http.StatusNotFound)
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
 		return
 	}
 	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
 	if err != nil {
-		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
-		log.Fatalf("error in string conversion: %v", err)
+		InternalServerError(w, r, err)
 		return
 	}
-	user := &User{}
+	user := &users.User{}
 	if err := json.NewDecoder(r.Body).Decode(user); err != nil {
-		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
-		log.Fatalf("error encoding data: %v", err)
+		InternalServerError(w, r, err)
 		return
 	}
 	user.ID = id
-	fmt.Println(user)
-	const query = `UPDATE user_management.users SET fname=$2, lname=$3, dob=$4, email=$5, phone_no=$6 WHERE id = $1;`
-	_, err = db.Exec(query, user.ID, user.Fname, user.Lname, user.DOB, user.Email, user.PhoneNo)
-	if err != nil {
-		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
-		log.Fatalf("error: %v", err)
-		return
-	}
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(empty{}); err != nil {
-		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
-		log.Fatalf("error encoding data: %v", err)
+	logging.FromContext(r.Context()).Debug("editing user", "user", user)
+	if err := u.s.EditUser(r.Context(), user); err != nil {
+		InternalServerError(w, r, err)
 		return
 	}
+	ReturnJSONEncoded(w, r, empty{})
 }
 
 // DeleteUser deletes a user
-func DeleteUser(w http.ResponseWriter, r *http.Request) {
+func (u *UserManagement) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "DELETE" {
-		http.Error(w, "404 not found.", http.StatusNotFound)
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
 		return
 	}
 	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
 	if err != nil {
-		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
-		log.Fatalf("error in string conversion: %v", err)
+		InternalServerError(w, r, err)
 		return
 	}
-	const query = `DELETE FROM user_management.users WHERE id = $1`
-	if _, err = db.Exec(query, id); err != nil {
-		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
-		log.Fatalf("error: %v", err)
-		return
-	}
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(empty{}); err != nil {
-		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
-		log.Fatalf("error encoding data: %v", err)
+	if err := u.s.DeleteUser(r.Context(), id); err != nil {
+		InternalServerError(w, r, err)
 		return
 	}
+	ReturnJSONEncoded(w, r, empty{})
 }
 
+// routes returns the mux of the api, every request being rate limited by
+// limiter, bounded by a timeout and replayed by keeper to its retries when
+// sent with an Idempotency-Key; the mux routes every path of the OpenAPI
+// document spec
+func routes(db *sql.DB, store *users.Store, limiter *ratelimit.Limiter, keeper *idempotency.Keeper) *openapi.Mux {
+	um := NewUserManagement(store)
+	mux := openapi.NewMux()
+	idempotent := Idempotent(keeper)
+	limit := RateLimit(limiter)
+	ipLimit := IPRateLimit(limiter)
+	timeout := Timeout(server.RequestTimeout)
+	mux.HandleFunc("/create", wrapper(um.CreateUser, idempotent, limit, timeout, Instrument)) // wrapper(um.CreateUser, idempotent, limit, BasicAuthentication, timeout, Instrument)) POST
+	mux.HandleFunc("/user", wrapper(um.GetUser, idempotent, limit, timeout, Instrument))      // wrapper(um.GetUser, idempotent, limit, BasicAuthentication, timeout, Instrument)) GET
+	mux.HandleFunc("/users", wrapper(um.GetAllUser, idempotent, limit, timeout, Instrument))  // wrapper(um.GetAllUser, idempotent, limit, BasicAuthentication, timeout, Instrument)) GET
+	mux.HandleFunc("/edit", wrapper(um.EditUser, idempotent, limit, timeout, Instrument))     // wrapper(um.EditUser, idempotent, limit, BasicAuthentication, timeout, Instrument)) PUT
+	mux.HandleFunc("/delete", wrapper(um.DeleteUser, idempotent, limit, timeout, Instrument)) // wrapper(um.DeleteUser, idempotent, limit, BasicAuthentication, timeout, Instrument)) DELETE
+
+	// webhook subscriptions, shared with the todolist-management service
+	hooks := webhooks.NewAPI(webhooks.NewStore(db))
+	mux.HandleFunc("/webhooks", wrapper(hooks.Subscriptions, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))              // GET | POST
+	mux.HandleFunc("/webhooks/{id}", wrapper(hooks.Subscription, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument))          // GET | PUT | DELETE
+	mux.HandleFunc("/webhooks/{id}/deliveries", wrapper(hooks.Deliveries, idempotent, limit, BasicAuthentication, ipLimit, timeout, Instrument)) // GET
+
+	// change feed of the users, lists and items, shared with the
+	// todolist-management service
+	mux.HandleFunc("/changes", wrapper(changes.NewFeed(db).Changes, limit, BasicAuthentication, ipLimit, Instrument)) // GET, streamed without a timeout
+
+	// probes of the orchestrator, also served by the admin server
+	mux.HandleFunc("/healthz", health.Live)                 // GET
+	mux.HandleFunc("/readyz", health.NewChecker(db).Status) // GET
+
+	// metrics, behind auth, also served by the admin server
+	mux.HandleFunc("/metrics", wrapper(metrics.Handler, BasicAuthentication, ipLimit)) // GET
+
+	// OpenAPI document of the api and its docs
+	mux.HandleFunc("/openapi.json", wrapper(openapi.Spec(spec), limit, Instrument)) // GET
+	mux.HandleFunc("/docs", wrapper(openapi.Docs, limit, Instrument))               // GET
+	mux.HandleFunc("/docs/{file}", wrapper(openapi.Assets, limit, Instrument))      // GET
+	return mux
+}
+
+// spec is the OpenAPI document of the api, describing every route of its mux
+//
+//go:embed openapi.json
+var spec []byte
+
 func main() {
-	// database connection
-	dbinfo := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", dbHost, dbPort, dbUser, dbPassword, dbName)
-	db, err = sql.Open("postgres", dbinfo)
+	// structured logs, of the level of LOG_LEVEL
+	slog.SetDefault(logging.New(os.Stdout, logging.ParseLevel(os.Getenv("LOG_LEVEL"))))
+
+	// traces, exported as set up by the OTEL_ environment
+	shutdownTracing, err := tracing.Setup("user-management")
 	if err != nil {
-		log.Fatalf("db connection error: %v", err)
+		slog.Error("tracing setup error", "err", err)
 		return
 	}
-	if err := db.Ping(); err != nil {
-		log.Fatalf("db ping error: %v", err)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), server.ShutdownTimeout)
+		defer cancel()
+		shutdownTracing(ctx)
+	}()
+
+	// database connection
+	db, err := DatabaseConnection()
+	if err != nil {
+		slog.Error("database error", "err", err)
 		return
 	}
 	defer db.Close()
 
-	// api pattern handlers
-	http.HandleFunc("/create", CreateUser) // wrapper(CreateUser, BasicAuthentication)) // POST
-	http.HandleFunc("/user", GetUser)      // wrapper(GetUser, BasicAuthentication))      // GET
-	http.HandleFunc("/users", GetAllUser)  // wrapper(GetAllUser, BasicAuthentication))  // GET
-	http.HandleFunc("/edit", EditUser)     // wrapper(EditUser, BasicAuthentication))     // PUT
-	http.HandleFunc("/delete", DeleteUser) // wrapper(DeleteUser, BasicAuthentication)) // DELETE
+	store := users.NewStore(db)
+	store.SetCache(cache.NewLRU("users", cacheSize, cacheTTL))
+
+	// deliveries of the webhooks, until the server shuts down
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		webhooks.NewDispatcher(db).Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// rate limits of the clients, by route, behind the TRUSTED_PROXIES
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), strings.Split(os.Getenv("TRUSTED_PROXIES"), ","))
+	if err != nil {
+		slog.Error("rate limiter setup error", "err", err)
+		return
+	}
+	limiter.Routes["/create"] = ratelimit.PerMinute(10)
+
+	// responses of the requests with an Idempotency-Key, kept for a day
+	keeper := idempotency.NewKeeper(idempotency.NewMemoryStore(), idempotencyTTL)
+
+	// api pattern handlers, the service not starting when they are out of
+	// step with the OpenAPI document
+	mux := routes(db, store, limiter, keeper)
+	if err := openapi.Check(spec, mux.Patterns()); err != nil {
+		slog.Error("openapi document error", "err", err)
+		return
+	}
+
+	// probes of the orchestrator and metrics, on the admin server
+	admin := server.AdminMux()
+	admin.HandleFunc("/healthz", health.Live)                // GET
+	admin.HandleFunc("/readyz", health.NewChecker(db).Ready) // GET
+	metrics.RegisterDBStats(db, "users")
+	admin.HandleFunc("/metrics", metrics.Handler) // GET
+
+	// gRPC api, over the same store
+	gs, err := server.NewGRPC(NewUserServer(store).Register, Authorized)
+	if err != nil {
+		slog.Error("grpc server setup error", "err", err)
+		return
+	}
+
+	// browser clients of the CORS_ allowed origins
+	origins, err := cors.FromEnv()
+	if err != nil {
+		slog.Error("cors setup error", "err", err)
+		return
+	}
 
-	if err := http.ListenAndServe(":8080", nil); err != nil {
-		log.Fatalf("server error: %v", err)
+	if err := server.Serve(tracing.Handler(logging.Handler(origins.Handler(mux))), admin, gs); err != nil {
+		slog.Error("server error", "err", err)
 	}
 }
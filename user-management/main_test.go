@@ -0,0 +1,106 @@
+package main
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/dbtest"
+	"github.com/Shivam010/go-rest-api/httperr"
+	"github.com/Shivam010/go-rest-api/idempotency"
+	"github.com/Shivam010/go-rest-api/openapi"
+	"github.com/Shivam010/go-rest-api/ratelimit"
+	users "github.com/Shivam010/go-rest-api/user-management/lib"
+)
+
+func TestRoutesMatchOpenAPI(t *testing.T) {
+	db := dbtest.Open(func(query string, args []driver.Value) ([][]driver.Value, error) {
+		return nil, nil
+	})
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := routes(db, users.NewStore(db), limiter, idempotency.NewKeeper(idempotency.NewMemoryStore(), time.Hour))
+	if err := openapi.Check(spec, mux.Patterns()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	db := dbtest.Open(func(query string, args []driver.Value) ([][]driver.Value, error) {
+		return nil, nil
+	})
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := routes(db, users.NewStore(db), limiter, idempotency.NewKeeper(idempotency.NewMemoryStore(), time.Hour))
+
+	// the error responses of the document are the bodies the handlers write
+	doc := struct {
+		Components struct {
+			Responses map[string]struct {
+				Content map[string]json.RawMessage `json:"content"`
+			} `json:"responses"`
+		} `json:"components"`
+	}{}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		t.Fatal(err)
+	}
+	for name, res := range doc.Components.Responses {
+		if _, ok := res.Content["application/json"]; !ok || len(res.Content) != 1 {
+			t.Errorf("response %s is not declared as application/json", name)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("DELETE", "/openapi.json", nil))
+	body := &httperr.Body{}
+	if err := json.Unmarshal(w.Body.Bytes(), body); err != nil || w.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("body = %q, %v", w.Body, err)
+	}
+	if w.Code != http.StatusNotFound || body.Status != http.StatusNotFound || body.Error != "404 not found." {
+		t.Errorf("DELETE /openapi.json = %d, %+v", w.Code, body)
+	}
+}
+
+func TestCreateUserRetried(t *testing.T) {
+	inserts := 0
+	db := dbtest.Open(func(query string, args []driver.Value) ([][]driver.Value, error) {
+		if strings.Contains(query, "INSERT INTO user_management.users") {
+			inserts++
+			return [][]driver.Value{{int64(inserts)}}, nil
+		}
+		return nil, nil
+	})
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := routes(db, users.NewStore(db), limiter, idempotency.NewKeeper(idempotency.NewMemoryStore(), time.Hour))
+
+	// /create is served without auth, its keys are scoped by the payload
+	send := func(body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/create", strings.NewReader(body))
+		r.Header.Set(idempotency.KeyHeader, "4f7d1c2e")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+	first := send(`{"fname":"Ada","email":"ada@example.com"}`)
+	if first.Code != http.StatusOK {
+		t.Fatalf("POST /create = %d %q", first.Code, first.Body)
+	}
+	retry := send(`{"fname":"Ada","email":"ada@example.com"}`)
+	if retry.Code != http.StatusOK || retry.Header().Get(idempotency.ReplayedHeader) != "true" || retry.Body.String() != first.Body.String() {
+		t.Errorf("retry = %d %q, replayed %q, want the response %q replayed", retry.Code, retry.Body, retry.Header().Get(idempotency.ReplayedHeader), first.Body)
+	}
+	if inserts != 1 {
+		t.Errorf("users inserted %d times, want once", inserts)
+	}
+}
@@ -0,0 +1,293 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Headers of a delivery
+const (
+	IDHeader        = "Webhook-ID"
+	EventHeader     = "Webhook-Event"
+	SignatureHeader = "Webhook-Signature"
+)
+
+// deliveryAttempts counts the attempts of the deliveries, by result
+var deliveryAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "webhook_delivery_attempts_total",
+	Help: "Number of the attempts of the webhook deliveries, by result: delivered, pending (to be retried) or dead.",
+}, []string{"result"})
+
+// Event is the body of a delivery, ID being the same for every attempt of
+// it so that receivers can drop the duplicates
+type Event struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Sign returns the signature of a body sent at t, the hex HMAC-SHA256 of
+// "t.body" keyed by the secret of the subscription, as sent in the
+// Webhook-Signature header: "t=<unix seconds>,v1=<signature>"
+func Sign(secret string, t time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", t.Unix())
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", t.Unix(), hex.EncodeToString(mac.Sum(nil)))
+}
+
+// pruneInterval is the interval between the prunings of the outbox
+const pruneInterval = time.Hour
+
+// ErrAddressNotAllowed is the error of a delivery to a URL resolving to a
+// loopback, private, link-local or otherwise non-public address
+var ErrAddressNotAllowed = errors.New("webhook address not allowed")
+
+// publicAddress refuses the connections to the addresses which are not
+// public, once resolved, so that a subscription can't reach the services
+// and the network they run in, whatever its URL, its redirects or the DNS
+// answers for its host
+func publicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %s", ErrAddressNotAllowed, host)
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, 100.64.0.0/10
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// Dispatcher sends the pending deliveries every Interval, Batch at a time,
+// retrying the failed ones after a delay doubling from BaseDelay up to
+// MaxDelay until they fail MaxAttempts times. A delivery succeeds when its
+// URL answers with a 2xx within Timeout, the URLs resolving to non-public
+// addresses being refused. The events whose deliveries all ended, delivered
+// or dead, are pruned from the outbox after Retention. Several dispatchers
+// can share a database, a delivery being leased to one of them while it is
+// sent
+type Dispatcher struct {
+	db          *sql.DB
+	client      *http.Client
+	Interval    time.Duration
+	Batch       int
+	Timeout     time.Duration
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	MaxAttempts int
+	Retention   time.Duration
+}
+
+// NewDispatcher returns a dispatcher checking for deliveries every 5
+// seconds, retrying the failed ones for about a day and keeping the ended
+// ones for a week
+func NewDispatcher(db *sql.DB) *Dispatcher {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, Control: publicAddress}
+	return &Dispatcher{
+		db: db,
+		// no proxy, which would dial the addresses in place of the dialer
+		client: &http.Client{Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConnsPerHost: 2,
+		}},
+		Interval:    5 * time.Second,
+		Batch:       50,
+		Timeout:     10 * time.Second,
+		BaseDelay:   30 * time.Second,
+		MaxDelay:    4 * time.Hour,
+		MaxAttempts: 15,
+		Retention:   7 * 24 * time.Hour,
+	}
+}
+
+// Run sends the deliveries every Interval, and prunes the outbox every
+// hour, until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+	pruned := time.Time{}
+	for {
+		if time.Since(pruned) >= pruneInterval {
+			if n, err := d.Prune(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "webhook prune error", "err", err)
+			} else if n > 0 {
+				slog.InfoContext(ctx, "webhook events pruned", "events", n)
+			}
+			pruned = time.Now()
+		}
+		// a full batch is followed by the next one straight away
+		for {
+			n, err := d.RunOnce(ctx)
+			if err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "webhook dispatch error", "err", err)
+			}
+			if err != nil || n < d.Batch {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pending is a delivery leased to the dispatcher
+type pending struct {
+	id       int64
+	attempts int
+	url      string
+	secret   string
+	event    Event
+}
+
+// RunOnce sends a batch of the deliveries due, and returns their number
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	batch, err := d.lease(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, p := range batch {
+		status, err := d.send(ctx, p)
+		if ctx.Err() != nil {
+			// shutting down, the lease expires and the delivery is sent again
+			return len(batch), ctx.Err()
+		}
+		if err := d.record(ctx, p, status, err); err != nil {
+			return len(batch), err
+		}
+	}
+	return len(batch), nil
+}
+
+// lease takes the deliveries due, pushing their next attempt past the time
+// they may take to be sent so that no other dispatcher sends them meanwhile
+func (d *Dispatcher) lease(ctx context.Context) ([]*pending, error) {
+	const query = `WITH due AS (
+			SELECT id FROM webhooks.deliveries WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at LIMIT $1 FOR UPDATE SKIP LOCKED
+		), leased AS (
+			UPDATE webhooks.deliveries d SET next_attempt_at = now() + $2 * interval '1 second'
+			FROM due WHERE d.id = due.id returning d.id, d.attempts, d.subscription_id, d.event_id
+		)
+		SELECT leased.id, leased.attempts, s.url, s.secret, o.id, o.type, o.created_at, o.payload
+		FROM leased JOIN webhooks.subscriptions s ON s.id = leased.subscription_id
+		JOIN webhooks.outbox o ON o.id = leased.event_id ORDER BY leased.id`
+	lease := (d.Timeout * time.Duration(d.Batch)).Seconds()
+	rows, err := d.db.QueryContext(ctx, query, d.Batch, lease)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	batch := []*pending{}
+	for rows.Next() {
+		p := &pending{}
+		if err := rows.Scan(&p.id, &p.attempts, &p.url, &p.secret, &p.event.ID, &p.event.Type, &p.event.CreatedAt, &p.event.Data); err != nil {
+			return nil, err
+		}
+		batch = append(batch, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// send posts a delivery to the URL of its subscription and returns the
+// status of the response
+func (d *Dispatcher) send(ctx context.Context, p *pending) (int, error) {
+	body, err := json.Marshal(&p.event)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", p.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IDHeader, strconv.FormatInt(p.event.ID, 10))
+	req.Header.Set(EventHeader, p.event.Type)
+	req.Header.Set(SignatureHeader, Sign(p.secret, time.Now(), body))
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+// record records the outcome of an attempt, scheduling the next one when it
+// failed or dead-lettering the delivery after MaxAttempts
+func (d *Dispatcher) record(ctx context.Context, p *pending, status int, err error) error {
+	attempts := p.attempts + 1
+	result, errText, next := StatusDelivered, "", time.Duration(0)
+	if err != nil {
+		result, errText, next = StatusPending, err.Error(), d.backoff(attempts)
+		if attempts >= d.MaxAttempts {
+			result = StatusDead
+		}
+	}
+	deliveryAttempts.WithLabelValues(result).Inc()
+	const query = `UPDATE webhooks.deliveries SET status = $2, attempts = $3, response_status = NULLIF($4, 0),
+		error = NULLIF($5, ''), next_attempt_at = now() + $6 * interval '1 second', updated_at = now() WHERE id = $1`
+	if _, err := d.db.ExecContext(ctx, query, p.id, result, attempts, status, errText, next.Seconds()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// backoff returns the delay before the attempt following the attempts made
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.BaseDelay
+	for i := 1; i < attempts && delay < d.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > d.MaxDelay {
+		delay = d.MaxDelay
+	}
+	return delay
+}
+
+// Prune deletes from the outbox the events older than Retention whose
+// deliveries all ended, along with their deliveries, and returns their
+// number
+func (d *Dispatcher) Prune(ctx context.Context) (int64, error) {
+	const query = `DELETE FROM webhooks.outbox o WHERE o.created_at < now() - $1 * interval '1 second'
+		AND NOT EXISTS (SELECT 1 FROM webhooks.deliveries d WHERE d.event_id = o.id AND d.status = 'pending')`
+	res, err := d.db.ExecContext(ctx, query, d.Retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
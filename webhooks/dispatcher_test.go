@@ -0,0 +1,182 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shivam010/go-rest-api/dbtest"
+)
+
+// outcome is the outcome of an attempt recorded by the dispatcher
+type outcome struct {
+	status   string
+	attempts int64
+	response int64
+	err      string
+	next     float64
+}
+
+// deliveryDB answers the lease of the dispatcher with a delivery to url,
+// attempted attempts times, and records the outcomes of its attempts
+func deliveryDB(url string, attempts int64) (*outcome, dbtest.Answer) {
+	out := &outcome{}
+	mu := sync.Mutex{}
+	leased := false
+	return out, func(query string, args []driver.Value) ([][]driver.Value, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.Contains(query, "WITH due"):
+			if leased {
+				return nil, nil
+			}
+			leased = true
+			created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+			return [][]driver.Value{{int64(1), attempts, url, "secret", int64(7), "item.added", created, []byte(`{"id":10}`)}}, nil
+		case strings.HasPrefix(query, "UPDATE webhooks.deliveries"):
+			out.status, out.attempts, out.response = args[1].(string), args[2].(int64), args[3].(int64)
+			out.err, out.next = args[4].(string), args[5].(float64)
+		}
+		return nil, nil
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int64
+		answer   int
+		want     outcome
+	}{
+		{"delivered", 0, http.StatusNoContent, outcome{status: StatusDelivered, attempts: 1, response: 204}},
+		{"retried", 2, http.StatusInternalServerError, outcome{status: StatusPending, attempts: 3, response: 500, err: "unexpected status 500", next: 120}},
+		{"retried after the max delay", 8, http.StatusBadGateway, outcome{status: StatusPending, attempts: 9, response: 502, err: "unexpected status 502", next: 3600}},
+		{"dead", 11, http.StatusInternalServerError, outcome{status: StatusDead, attempts: 12, response: 500, err: "unexpected status 500", next: 3600}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			body := []byte{}
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, body = r, readAll(t, r.Body)
+				w.WriteHeader(tt.answer)
+			}))
+			defer receiver.Close()
+
+			out, answer := deliveryDB(receiver.URL, tt.attempts)
+			d := NewDispatcher(dbtest.Open(answer))
+			// the receiver listens on the loopback
+			d.client = receiver.Client()
+			d.MaxDelay, d.MaxAttempts = time.Hour, 12
+			if n, err := d.RunOnce(context.Background()); n != 1 || err != nil {
+				t.Fatalf("RunOnce() = %d, %v, want 1 delivery", n, err)
+			}
+
+			if got == nil {
+				t.Fatal("nothing delivered")
+			}
+			if got.Header.Get(IDHeader) != "7" || got.Header.Get(EventHeader) != "item.added" {
+				t.Errorf("headers = %v, want the id and type of the event", got.Header)
+			}
+			if want := `{"id":7,"type":"item.added","created_at":"2025-01-02T03:04:05Z","data":{"id":10}}`; string(body) != want {
+				t.Errorf("body = %s, want %s", body, want)
+			}
+			signature := got.Header.Get(SignatureHeader)
+			sent, err := strconv.ParseInt(strings.TrimPrefix(strings.Split(signature, ",")[0], "t="), 10, 64)
+			if err != nil || !hmac.Equal([]byte(signature), []byte(Sign("secret", time.Unix(sent, 0), body))) {
+				t.Errorf("%s = %q, want the HMAC of the body keyed by the secret", SignatureHeader, signature)
+			}
+			if *out != tt.want {
+				t.Errorf("outcome = %+v, want %+v", *out, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispatcherRefusesPrivateAddresses(t *testing.T) {
+	hit := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer receiver.Close()
+
+	for _, url := range []string{receiver.URL, "http://localhost:" + receiver.URL[strings.LastIndex(receiver.URL, ":")+1:]} {
+		out, answer := deliveryDB(url, 0)
+		d := NewDispatcher(dbtest.Open(answer))
+		if _, err := d.RunOnce(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if out.status != StatusPending || !strings.Contains(out.err, ErrAddressNotAllowed.Error()) {
+			t.Errorf("delivery to %s = %+v, want it refused", url, *out)
+		}
+	}
+	if hit {
+		t.Error("a loopback address was delivered to")
+	}
+}
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+		{"127.0.0.1:80", false},
+		{"[::1]:80", false},
+		{"10.1.2.3:80", false},
+		{"172.16.0.1:80", false},
+		{"192.168.1.1:80", false},
+		{"169.254.169.254:80", false},
+		{"100.64.0.1:80", false},
+		{"0.0.0.0:80", false},
+		{"[fd00::1]:80", false},
+		{"[fe80::1]:80", false},
+		{"[::ffff:127.0.0.1]:80", false},
+	}
+	for _, tt := range tests {
+		err := publicAddress("tcp", tt.address, nil)
+		if allowed := err == nil; allowed != tt.allowed {
+			t.Errorf("publicAddress(%s) = %v, want allowed %v", tt.address, err, tt.allowed)
+		}
+		if err != nil && !errors.Is(err, ErrAddressNotAllowed) {
+			t.Errorf("publicAddress(%s) = %v, want %v", tt.address, err, ErrAddressNotAllowed)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		sub  Subscription
+		want error
+	}{
+		{"valid", Subscription{URL: "https://example.com/hook", Events: []string{"item.added", "user.deleted"}}, nil},
+		{"invalid url", Subscription{URL: "ftp://example.com", Events: []string{"item.added"}}, ErrInvalidURL},
+		{"no event types", Subscription{URL: "https://example.com/hook"}, ErrNoEventTypes},
+		{"unknown event type", Subscription{URL: "https://example.com/hook", Events: []string{"item.added", "item.exploded"}}, ErrUnknownEventType},
+	}
+	for _, tt := range tests {
+		if err := validate(&tt.sub); !errors.Is(err, tt.want) {
+			t.Errorf("%s: validate() = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func readAll(t *testing.T, r io.Reader) []byte {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Error(err)
+	}
+	return b
+}
@@ -0,0 +1,155 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Shivam010/go-rest-api/httperr"
+	"github.com/Shivam010/go-rest-api/logging"
+	"github.com/Shivam010/go-rest-api/tracing"
+)
+
+// maxDeliveries is the largest page of the delivery log
+const maxDeliveries = 100
+
+// API is the HTTP API of the subscriptions, served by both the services
+type API struct {
+	s *Store
+}
+
+// NewAPI returns the API of the subscriptions of a store
+func NewAPI(s *Store) *API {
+	return &API{s}
+}
+
+// Subscriptions lists the subscriptions on GET and creates one on POST, the
+// only response including the secret of the subscription
+func (a *API) Subscriptions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		subs, err := a.s.ListSubscriptions(r.Context())
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, subs)
+	case "POST":
+		sub := &Subscription{}
+		if err := json.NewDecoder(r.Body).Decode(sub); err != nil {
+			httperr.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sub, err := a.s.CreateSubscription(r.Context(), sub)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, sub)
+	default:
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+	}
+}
+
+// Subscription returns a subscription on GET, updates it on PUT and deletes
+// it on DELETE
+func (a *API) Subscription(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		httperr.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch r.Method {
+	case "GET":
+		sub, err := a.s.GetSubscription(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, sub)
+	case "PUT":
+		sub := &Subscription{}
+		if err := json.NewDecoder(r.Body).Decode(sub); err != nil {
+			httperr.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sub.ID = id
+		if err := a.s.UpdateSubscription(r.Context(), sub); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		sub, err := a.s.GetSubscription(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, sub)
+	case "DELETE":
+		if err := a.s.DeleteSubscription(r.Context(), id); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+	}
+}
+
+// Deliveries returns the delivery log of a subscription, the latest first,
+// ?limit= at a time and before the delivery ?before= when given
+func (a *API) Deliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		httperr.Error(w, "404 not found.", http.StatusNotFound)
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		httperr.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q := r.URL.Query()
+	before, limit := int64(0), maxDeliveries
+	if v := q.Get("before"); v != "" {
+		if before, err = strconv.ParseInt(v, 10, 64); err != nil {
+			httperr.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > maxDeliveries {
+			httperr.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+	deliveries, err := a.s.Deliveries(r.Context(), id, before, limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+// writeError answers with the status of err
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		httperr.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrInvalidURL), errors.Is(err, ErrNoEventTypes), errors.Is(err, ErrUnknownEventType):
+		httperr.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		ref := ""
+		if id := tracing.TraceID(r.Context()); id != "" {
+			ref = " (trace id " + id + ")"
+		}
+		httperr.Error(w, "500 Internal Server Error"+ref, http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("request failed", "err", err)
+	}
+}
+
+// writeJSON writes v as the JSON body of a response of status code
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
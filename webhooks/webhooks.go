@@ -0,0 +1,254 @@
+// Package webhooks delivers the events of the services to the URLs of their
+// subscriptions. An event is written to the outbox in the transaction of the
+// change it is about, along with a delivery to every subscription to its
+// type, and the Dispatcher sends the deliveries once committed
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Webhook errors
+var (
+	ErrNotFound         = errors.New("webhook not found")
+	ErrInvalidURL       = errors.New("invalid webhook url")
+	ErrNoEventTypes     = errors.New("webhook without event types")
+	ErrUnknownEventType = errors.New("unknown webhook event type")
+)
+
+// EventTypes are the types of the events of both the services, the ones a
+// subscription can be to
+var EventTypes = []string{
+	"item.added", "item.updated", "item.completed", "item.deleted",
+	"list.created", "list.renamed", "list.archived", "list.unarchived", "list.deleted",
+	"user.created", "user.updated", "user.deleted",
+}
+
+// Delivery statuses, a delivery is dead once it failed MaxAttempts times
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead"
+)
+
+// Subscription to the events of some types, the secret signing its
+// deliveries is only returned once created
+type Subscription struct {
+	ID        int64     `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Delivery of an event to a subscription
+type Delivery struct {
+	ID             int64           `json:"id"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// Enqueue writes an event to the outbox in tx, with a delivery to every
+// subscription to its type; nothing is sent unless tx is committed
+func Enqueue(ctx context.Context, tx *sql.Tx, typ string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	const query = `WITH event AS (
+			INSERT INTO webhooks.outbox (type, payload) VALUES ($1, $2) returning id
+		)
+		INSERT INTO webhooks.deliveries (subscription_id, event_id)
+		SELECT s.id, event.id FROM webhooks.subscriptions s, event WHERE $1 = ANY(s.events)`
+	if _, err := tx.ExecContext(ctx, query, typ, payload); err != nil {
+		return err
+	}
+	return nil
+}
+
+// subscriptionColumns are the subscriptions columns read by scanSubscription,
+// in order, the secret aside
+const subscriptionColumns = `id, url, events, created_at`
+
+// scanner is implemented by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSubscription reads the subscriptionColumns of a row into sub
+func scanSubscription(row scanner, sub *Subscription) error {
+	return row.Scan(&sub.ID, &sub.URL, pq.Array(&sub.Events), &sub.CreatedAt)
+}
+
+// Store of the subscriptions and of their deliveries
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore returns the store of the subscriptions in db
+func NewStore(db *sql.DB) *Store {
+	return &Store{db}
+}
+
+// validate checks the URL and the event types of a subscription, the
+// addresses the URL resolves to being checked on every delivery
+func validate(sub *Subscription) error {
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+	if len(sub.Events) == 0 {
+		return ErrNoEventTypes
+	}
+	for _, typ := range sub.Events {
+		if typ == "" {
+			return ErrNoEventTypes
+		}
+		if !slices.Contains(EventTypes, typ) {
+			return fmt.Errorf("%w: %q", ErrUnknownEventType, typ)
+		}
+	}
+	return nil
+}
+
+// CreateSubscription creates a subscription, with a random secret unless
+// one is given
+func (s *Store) CreateSubscription(ctx context.Context, sub *Subscription) (*Subscription, error) {
+	if err := validate(sub); err != nil {
+		return nil, err
+	}
+	if sub.Secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		sub.Secret = hex.EncodeToString(b)
+	}
+	const query = `INSERT INTO webhooks.subscriptions (url, events, secret) VALUES ($1, $2, $3) returning id, created_at`
+	if err := s.db.QueryRowContext(ctx, query, sub.URL, pq.Array(sub.Events), sub.Secret).Scan(&sub.ID, &sub.CreatedAt); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// GetSubscription returns a subscription
+func (s *Store) GetSubscription(ctx context.Context, id int64) (*Subscription, error) {
+	const query = `SELECT ` + subscriptionColumns + ` FROM webhooks.subscriptions WHERE id = $1`
+	sub := &Subscription{}
+	if err := scanSubscription(s.db.QueryRowContext(ctx, query, id), sub); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return sub, nil
+}
+
+// ListSubscriptions returns all the subscriptions
+func (s *Store) ListSubscriptions(ctx context.Context) ([]*Subscription, error) {
+	const query = `SELECT ` + subscriptionColumns + ` FROM webhooks.subscriptions ORDER BY id`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	subs := []*Subscription{}
+	for rows.Next() {
+		sub := &Subscription{}
+		if err := scanSubscription(rows, sub); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// UpdateSubscription updates the URL and the event types of a subscription,
+// and its secret when one is given; the deliveries of the events already
+// written to the outbox are left as they are
+func (s *Store) UpdateSubscription(ctx context.Context, sub *Subscription) error {
+	if err := validate(sub); err != nil {
+		return err
+	}
+	const query = `UPDATE webhooks.subscriptions SET url = $2, events = $3, secret = COALESCE(NULLIF($4, ''), secret) WHERE id = $1`
+	res, err := s.db.ExecContext(ctx, query, sub.ID, sub.URL, pq.Array(sub.Events), sub.Secret)
+	if err != nil {
+		return err
+	}
+	return mustAffect(res)
+}
+
+// DeleteSubscription deletes a subscription along with its deliveries
+func (s *Store) DeleteSubscription(ctx context.Context, id int64) error {
+	const query = `DELETE FROM webhooks.subscriptions WHERE id = $1`
+	res, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return mustAffect(res)
+}
+
+// Deliveries returns the last deliveries of a subscription, the latest
+// first, before the delivery before when given
+func (s *Store) Deliveries(ctx context.Context, id int64, before int64, limit int) ([]*Delivery, error) {
+	if _, err := s.GetSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+	const query = `SELECT d.id, o.id, o.type, o.payload, d.status, d.attempts,
+			CASE WHEN d.status = 'pending' THEN d.next_attempt_at END,
+			COALESCE(d.response_status, 0), COALESCE(d.error, ''), o.created_at, d.updated_at
+		FROM webhooks.deliveries d JOIN webhooks.outbox o ON o.id = d.event_id
+		WHERE d.subscription_id = $1 AND ($2 = 0 OR d.id < $2) ORDER BY d.id DESC LIMIT $3`
+	rows, err := s.db.QueryContext(ctx, query, id, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	deliveries := []*Delivery{}
+	for rows.Next() {
+		d := &Delivery{}
+		if err := rows.Scan(&d.ID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+			&d.NextAttemptAt, &d.ResponseStatus, &d.Error, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// mustAffect returns ErrNotFound when a statement changed no subscription
+func mustAffect(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package webhooks_test
+
+import (
+	"slices"
+	"testing"
+
+	todolist "github.com/Shivam010/go-rest-api/todolist-management/lib"
+	users "github.com/Shivam010/go-rest-api/user-management/lib"
+	"github.com/Shivam010/go-rest-api/webhooks"
+)
+
+// TestEventTypes checks the events enqueued by the services can be
+// subscribed to
+func TestEventTypes(t *testing.T) {
+	for _, typ := range []string{
+		todolist.EventItemAdded, todolist.EventItemUpdated, todolist.EventItemCompleted, todolist.EventItemDeleted,
+		todolist.EventListCreated, todolist.EventListRenamed, todolist.EventListArchived, todolist.EventListUnarchived,
+		todolist.EventListDeleted,
+		users.EventUserCreated, users.EventUserUpdated, users.EventUserDeleted,
+	} {
+		if !slices.Contains(webhooks.EventTypes, typ) {
+			t.Errorf("%s is not one of the webhooks.EventTypes", typ)
+		}
+	}
+}